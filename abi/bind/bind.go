@@ -0,0 +1,201 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bind
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"text/template"
+)
+
+// Generate renders a Go source file binding schema's contract to
+// WasmRuntime.CallContract. pkgName is the package the generated file will
+// belong to, typically the caller's off-chain client package.
+func Generate(pkgName string, schema ContractSchema) ([]byte, error) {
+    data, err := newTemplateData(pkgName, schema)
+    if err != nil {
+        return nil, err
+    }
+
+    var buf bytes.Buffer
+    if err := bindTemplate.Execute(&buf, data); err != nil {
+        return nil, fmt.Errorf("bind: failed to render template: %w", err)
+    }
+
+    formatted, err := format.Source(buf.Bytes())
+    if err != nil {
+        return nil, fmt.Errorf("bind: failed to gofmt generated source: %w", err)
+    }
+    return formatted, nil
+}
+
+type templateData struct {
+    Package   string
+    Type      string
+    Functions []templateFunction
+    Events    []templateEvent
+}
+
+type templateField struct {
+    Name string
+    Type string
+}
+
+type templateFunction struct {
+    Name    string
+    Params  []templateField
+    Returns []templateField
+}
+
+type templateEvent struct {
+    Name   string
+    Topics []templateField
+    Data   []templateField
+}
+
+func newTemplateData(pkgName string, schema ContractSchema) (*templateData, error) {
+    data := &templateData{
+        Package: pkgName,
+        Type:    schema.Name,
+    }
+
+    for _, fn := range schema.Functions {
+        params, err := toTemplateFields(fn.Params)
+        if err != nil {
+            return nil, fmt.Errorf("bind: function %s: %w", fn.Name, err)
+        }
+        returns, err := toTemplateFields(fn.Returns)
+        if err != nil {
+            return nil, fmt.Errorf("bind: function %s: %w", fn.Name, err)
+        }
+        data.Functions = append(data.Functions, templateFunction{
+            Name:    fn.Name,
+            Params:  params,
+            Returns: returns,
+        })
+    }
+
+    for _, ev := range schema.Events {
+        topics, err := toTemplateFields(ev.Topics)
+        if err != nil {
+            return nil, fmt.Errorf("bind: event %s: %w", ev.Name, err)
+        }
+        fields, err := toTemplateFields(ev.Data)
+        if err != nil {
+            return nil, fmt.Errorf("bind: event %s: %w", ev.Name, err)
+        }
+        data.Events = append(data.Events, templateEvent{
+            Name:   ev.Name,
+            Topics: topics,
+            Data:   fields,
+        })
+    }
+
+    return data, nil
+}
+
+func toTemplateFields(fields []FieldSchema) ([]templateField, error) {
+    out := make([]templateField, 0, len(fields))
+    for _, f := range fields {
+        t, err := goType(f.Type)
+        if err != nil {
+            return nil, err
+        }
+        out = append(out, templateField{Name: f.Name, Type: t})
+    }
+    return out, nil
+}
+
+var bindTemplate = template.Must(template.New("bind").Parse(`// Code generated by hyperbind. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"context"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/x/contracts/runtime"
+)
+
+// {{.Type}} is a typed client for the deployed contract at a fixed address.
+type {{.Type}} struct {
+	runtime *runtime.WasmRuntime
+	addr    codec.Address
+}
+
+// New{{.Type}} binds a {{.Type}} client to addr.
+func New{{.Type}}(rt *runtime.WasmRuntime, addr codec.Address) *{{.Type}} {
+	return &{{.Type}}{runtime: rt, addr: addr}
+}
+{{range .Functions}}
+// {{.Name}} calls the {{.Name}} entrypoint. callFlags is forwarded to
+// CallInfo.CallFlags as-is, so a caller that only needs e.g. runtime.ReadOnly
+// never has to request runtime.All.
+func (c *{{$.Type}}) {{.Name}}(ctx context.Context, actor codec.Address, fuel uint64, callFlags runtime.CallFlags{{range .Params}}, {{.Name}} {{.Type}}{{end}}) ({{range .Returns}}{{.Name}} {{.Type}}, {{end}}err error) {
+	params, err := runtime.Serialize(struct {
+{{range .Params}}		{{.Name}} {{.Type}}
+{{end}}	}{
+{{range .Params}}		{{.Name}}: {{.Name}},
+{{end}}	})
+	if err != nil {
+		return
+	}
+
+{{if .Returns}}	result, err := c.runtime.CallContract(ctx, &runtime.CallInfo{
+{{else}}	_, err = c.runtime.CallContract(ctx, &runtime.CallInfo{
+{{end}}		Contract:     c.addr,
+		Actor:        actor,
+		FunctionName: "{{.Name}}",
+		Params:       params,
+		Fuel:         fuel,
+		CallFlags:    callFlags,
+	})
+	if err != nil {
+		return
+	}
+{{if .Returns}}
+	var out struct {
+{{range .Returns}}		{{.Name}} {{.Type}}
+{{end}}	}
+	if err = runtime.Deserialize(result.Return, &out); err != nil {
+		return
+	}
+	return {{range .Returns}}out.{{.Name}}, {{end}}nil
+{{else}}
+	return
+{{end}}}
+{{end}}
+{{range .Events}}
+// {{$.Type}}{{.Name}}Event is the typed payload of a {{.Name}} event.
+type {{$.Type}}{{.Name}}Event struct {
+{{range .Topics}}	{{.Name}} {{.Type}}
+{{end}}{{range .Data}}	{{.Name}} {{.Type}}
+{{end}}}
+
+// Watch{{.Name}} subscribes to {{.Name}} events emitted by this contract.
+func (c *{{$.Type}}) Watch{{.Name}}(ctx context.Context) (<-chan {{$.Type}}{{.Name}}Event, error) {
+	raw, err := c.runtime.WatchLogs(ctx, runtime.EventFilter{
+		Contracts:  []codec.Address{c.addr},
+		NameHashes: [][32]byte{runtime.TopicHash("{{.Name}}")},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan {{$.Type}}{{.Name}}Event)
+	go func() {
+		defer close(out)
+		for ev := range raw {
+			var decoded {{$.Type}}{{.Name}}Event
+			if err := c.runtime.UnpackLog(&decoded, "{{.Name}}", ev); err != nil {
+				continue
+			}
+			out <- decoded
+		}
+	}()
+	return out, nil
+}
+{{end}}
+`))
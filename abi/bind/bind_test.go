@@ -0,0 +1,107 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bind
+
+import (
+    "go/format"
+    "go/parser"
+    "go/token"
+    "testing"
+
+    "github.com/stretchr/testify/require"
+)
+
+// testSchema exercises every per-branch case Generate's template has to get
+// right: a zero-param/zero-return function, a function with both params and
+// returns, and an event split into topic and data fields.
+var testSchema = ContractSchema{
+    Name: "Token",
+    Functions: []FunctionSchema{
+        {Name: "reset"},
+        {
+            Name:    "transfer",
+            Params:  []FieldSchema{{Name: "to", Type: "address"}, {Name: "amount", Type: "uint64"}},
+            Returns: []FieldSchema{{Name: "ok", Type: "bool"}},
+        },
+    },
+    Events: []EventSchema{
+        {
+            Name:   "Transfer",
+            Topics: []FieldSchema{{Name: "from", Type: "address"}, {Name: "to", Type: "address"}},
+            Data:   []FieldSchema{{Name: "amount", Type: "uint64"}},
+        },
+    },
+}
+
+// TestGenerateProducesValidGo is a golden-file-style check that Generate's
+// output actually parses and is already gofmt'd, since a template typo here
+// only surfaces once a generated client fails to compile downstream.
+func TestGenerateProducesValidGo(t *testing.T) {
+    require := require.New(t)
+
+    src, err := Generate("client", testSchema)
+    require.NoError(err)
+
+    fset := token.NewFileSet()
+    _, err = parser.ParseFile(fset, "client.go", src, parser.AllErrors)
+    require.NoError(err, "generated source must parse as valid Go")
+
+    formatted, err := format.Source(src)
+    require.NoError(err)
+    require.Equal(string(formatted), string(src), "Generate must already return gofmt'd output")
+}
+
+// TestGenerateZeroParamZeroReturnFunction confirms a function with neither
+// params nor returns gets a plain Serialize(struct{}{}) call and a bare
+// named return, not a malformed empty returns list.
+func TestGenerateZeroParamZeroReturnFunction(t *testing.T) {
+    require := require.New(t)
+
+    src, err := Generate("client", testSchema)
+    require.NoError(err)
+
+    require.Contains(string(src), "func (c *Token) reset(ctx context.Context, actor codec.Address, fuel uint64, callFlags runtime.CallFlags) (err error) {")
+    require.Contains(string(src), "\n\treturn\n}", "a zero-return function should end in a bare named return, not a synthesized zero-value literal")
+}
+
+// TestGenerateFunctionWithParamsAndReturns confirms params are threaded
+// into the serialized struct and returns are threaded out of it.
+func TestGenerateFunctionWithParamsAndReturns(t *testing.T) {
+    require := require.New(t)
+
+    src, err := Generate("client", testSchema)
+    require.NoError(err)
+
+    require.Contains(string(src), "func (c *Token) transfer(ctx context.Context, actor codec.Address, fuel uint64, callFlags runtime.CallFlags, to codec.Address, amount uint64) (ok bool, err error) {")
+    require.Contains(string(src), "return out.ok, nil")
+}
+
+// TestGenerateEventSplitsTopicsAndData confirms an event's topic and data
+// fields both land on the generated event struct.
+func TestGenerateEventSplitsTopicsAndData(t *testing.T) {
+    require := require.New(t)
+
+    src, err := Generate("client", testSchema)
+    require.NoError(err)
+
+    require.Contains(string(src), "type TokenTransferEvent struct")
+    require.Contains(string(src), "from   codec.Address")
+    require.Contains(string(src), "to     codec.Address")
+    require.Contains(string(src), "amount uint64")
+    require.Contains(string(src), "func (c *Token) WatchTransfer(ctx context.Context) (<-chan TokenTransferEvent, error) {")
+}
+
+// TestGenerateCallFlagsIsCallerControlled guards against regressing back to
+// a hardcoded runtime.All on every generated call: an off-chain client
+// built from this generator should be able to request a narrower set, such
+// as runtime.ReadOnly, for a call that only reads state.
+func TestGenerateCallFlagsIsCallerControlled(t *testing.T) {
+    require := require.New(t)
+
+    src, err := Generate("client", testSchema)
+    require.NoError(err)
+
+    require.NotContains(string(src), "CallFlags:    runtime.All")
+    require.Contains(string(src), "CallFlags:    callFlags")
+}
@@ -0,0 +1,56 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package bind generates typed Go wrappers around WasmRuntime.CallContract
+// from a contract's exported schema, the same way abigen does for Solidity
+// contracts compiled with geth.
+package bind
+
+import "fmt"
+
+// ContractSchema is the JSON document dumped by the Rust contract toolchain
+// describing one contract's exported functions and events.
+type ContractSchema struct {
+    Name      string           `json:"name"`
+    Functions []FunctionSchema `json:"functions"`
+    Events    []EventSchema    `json:"events"`
+}
+
+// FunctionSchema describes one exported contract function.
+type FunctionSchema struct {
+    Name    string        `json:"name"`
+    Params  []FieldSchema `json:"params"`
+    Returns []FieldSchema `json:"returns"`
+}
+
+// EventSchema describes one event a contract may emit, split into indexed
+// topic fields and unindexed data fields, mirroring Event's Topics/Data
+// split in the runtime package.
+type EventSchema struct {
+    Name   string        `json:"name"`
+    Topics []FieldSchema `json:"topics"`
+    Data   []FieldSchema `json:"data"`
+}
+
+// FieldSchema is a single named, typed value serialized by the runtime's
+// Serialize codec.
+type FieldSchema struct {
+    Name string `json:"name"`
+    Type string `json:"type"`
+}
+
+// goType maps a schema type name to the Go type Serialize/Deserialize
+// produces for it. An unrecognized type is rejected with an error naming it,
+// so Generate fails loudly rather than silently mis-binding.
+func goType(t string) (string, error) {
+    switch t {
+    case "bool", "string", "uint8", "uint16", "uint32", "uint64", "int8", "int16", "int32", "int64":
+        return t, nil
+    case "address":
+        return "codec.Address", nil
+    case "bytes":
+        return "[]byte", nil
+    default:
+        return "", fmt.Errorf("bind: unsupported schema type %q", t)
+    }
+}
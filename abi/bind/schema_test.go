@@ -0,0 +1,46 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bind
+
+import (
+    "testing"
+
+    "github.com/stretchr/testify/require"
+)
+
+func TestGoType(t *testing.T) {
+    require := require.New(t)
+
+    tests := []struct {
+        schemaType string
+        goType     string
+    }{
+        {"bool", "bool"},
+        {"string", "string"},
+        {"uint8", "uint8"},
+        {"uint16", "uint16"},
+        {"uint32", "uint32"},
+        {"uint64", "uint64"},
+        {"int8", "int8"},
+        {"int16", "int16"},
+        {"int32", "int32"},
+        {"int64", "int64"},
+        {"address", "codec.Address"},
+        {"bytes", "[]byte"},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.schemaType, func(t *testing.T) {
+            got, err := goType(tt.schemaType)
+            require.NoError(err)
+            require.Equal(tt.goType, got)
+        })
+    }
+
+    t.Run("unsupported type fails loudly instead of passing through silently", func(t *testing.T) {
+        _, err := goType("int128")
+        require.Error(err)
+        require.Contains(err.Error(), "int128")
+    })
+}
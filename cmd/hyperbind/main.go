@@ -0,0 +1,54 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Command hyperbind reads a contract schema dumped by the Rust contract
+// toolchain and writes a typed Go client for it, analogous to abigen.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ava-labs/hypersdk/abi/bind"
+)
+
+func main() {
+    schemaPath := flag.String("schema", "", "path to the contract's JSON schema")
+    outPath := flag.String("out", "", "path to write the generated Go file")
+    pkgName := flag.String("package", "main", "package name for the generated file")
+    flag.Parse()
+
+    if err := run(*schemaPath, *outPath, *pkgName); err != nil {
+        fmt.Fprintln(os.Stderr, "hyperbind:", err)
+        os.Exit(1)
+    }
+}
+
+func run(schemaPath, outPath, pkgName string) error {
+    if schemaPath == "" || outPath == "" {
+        return fmt.Errorf("both -schema and -out are required")
+    }
+
+    raw, err := os.ReadFile(schemaPath)
+    if err != nil {
+        return fmt.Errorf("failed to read schema: %w", err)
+    }
+
+    var schema bind.ContractSchema
+    if err := json.Unmarshal(raw, &schema); err != nil {
+        return fmt.Errorf("failed to parse schema: %w", err)
+    }
+
+    src, err := bind.Generate(pkgName, schema)
+    if err != nil {
+        return fmt.Errorf("failed to generate binding: %w", err)
+    }
+
+    if err := os.WriteFile(outPath, src, 0o644); err != nil {
+        return fmt.Errorf("failed to write %s: %w", outPath, err)
+    }
+
+    return nil
+}
@@ -0,0 +1,125 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package runtime
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+
+	"github.com/bytecodealliance/wasmtime-go/v25"
+)
+
+// ValidationMode controls how a WasmRuntime's validator chain is evaluated.
+type ValidationMode int
+
+const (
+    // ValidateFailFast stops at the first validator that returns an error,
+    // the same semantics a single ModuleValidator always had.
+    ValidateFailFast ValidationMode = iota
+    // ValidateAllMustPass runs every validator in the chain and joins all
+    // errors returned, useful when validators check unrelated properties
+    // and an operator wants every failure reported at once.
+    ValidateAllMustPass
+)
+
+// CacheTierStats reports the hit count for one tier of a WasmRuntime's
+// cache chain, in registration order (tier 0 is probed first).
+type CacheTierStats struct {
+    Tier int
+    Hits uint64
+}
+
+// RegisterValidator appends validator to the end of the validation chain.
+func (r *WasmRuntime) RegisterValidator(validator ModuleValidator) {
+    r.validators = append(r.validators, validator)
+}
+
+// RegisterCache appends cache as the next, slower, tier probed after every
+// tier already registered. A typical layering is an in-memory LRU as tier 0
+// over a shared Redis/BadgerDB-backed cache as tier 1.
+func (r *WasmRuntime) RegisterCache(cache CacheStrategy) {
+    r.caches = append(r.caches, cache)
+    r.tierHits = append(r.tierHits, 0)
+}
+
+// SetValidationMode controls whether the validator chain fails fast or
+// requires every validator to run. The default is ValidateFailFast.
+func (r *WasmRuntime) SetValidationMode(mode ValidationMode) {
+    r.validationMode = mode
+}
+
+// runValidators runs the validator chain against contractBytes. If state
+// implements ManifestStore and a manifest is stored for id, any validator
+// that also implements ManifestValidator checks the module against it.
+func (r *WasmRuntime) runValidators(ctx context.Context, state StateManager, id []byte, contractBytes []byte) error {
+    manifest, err := lookupManifest(ctx, state, id)
+    if err != nil {
+        return err
+    }
+
+    validate := func(v ModuleValidator) error {
+        if err := v.ValidateModule(ctx, contractBytes); err != nil {
+            return err
+        }
+        if manifest == nil {
+            return nil
+        }
+        if mv, ok := v.(ManifestValidator); ok {
+            return mv.ValidateManifest(ctx, contractBytes, *manifest)
+        }
+        return nil
+    }
+
+    if r.validationMode == ValidateAllMustPass {
+        var errs []error
+        for _, v := range r.validators {
+            if err := validate(v); err != nil {
+                errs = append(errs, err)
+            }
+        }
+        return errors.Join(errs...)
+    }
+
+    for _, v := range r.validators {
+        if err := validate(v); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// getFromCacheChain probes the cache chain in order. On a hit at tier i, the
+// module is written through to every faster tier before i so it is warm
+// there the next time it is requested.
+func (r *WasmRuntime) getFromCacheChain(id string) (*wasmtime.Module, int, bool) {
+    for i, c := range r.caches {
+        if mod, ok := c.GetModule(id); ok {
+            for j := 0; j < i; j++ {
+                r.caches[j].PutModule(id, mod)
+            }
+            if i < len(r.tierHits) {
+                atomic.AddUint64(&r.tierHits[i], 1)
+            }
+            return mod, i, true
+        }
+    }
+    return nil, -1, false
+}
+
+func (r *WasmRuntime) putToCacheChain(id string, mod *wasmtime.Module) {
+    for _, c := range r.caches {
+        c.PutModule(id, mod)
+    }
+}
+
+// GetCacheTierStats reports the hit count of every registered cache tier,
+// in probe order.
+func (r *WasmRuntime) GetCacheTierStats() []CacheTierStats {
+    stats := make([]CacheTierStats, len(r.tierHits))
+    for i := range r.tierHits {
+        stats[i] = CacheTierStats{Tier: i, Hits: atomic.LoadUint64(&r.tierHits[i])}
+    }
+    return stats
+}
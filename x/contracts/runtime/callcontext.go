@@ -0,0 +1,72 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package runtime
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/codec"
+)
+
+// CallContext pairs a WasmRuntime with a base CallInfo whose fields are
+// used to fill in whatever a given CallContract invocation leaves unset,
+// the way WithDefaults lets a caller fix State/Fuel/CallFlags once and
+// issue many calls that only vary by Contract/FunctionName/Params.
+type CallContext struct {
+    r        *WasmRuntime
+    defaults CallInfo
+}
+
+func NewCallContext(r *WasmRuntime, defaults CallInfo) CallContext {
+    return CallContext{r: r, defaults: defaults}
+}
+
+// WithCallFlags returns a CallContext whose calls run with flags unless the
+// call passed to CallContract sets its own CallFlags.
+func (c CallContext) WithCallFlags(flags CallFlags) CallContext {
+    c.defaults.CallFlags = flags
+    return c
+}
+
+// CallContract merges callInfo over c's defaults and executes it.
+func (c CallContext) CallContract(ctx context.Context, callInfo *CallInfo) (*ExecutionResult, error) {
+    merged := c.defaults
+
+    if callInfo.State != nil {
+        merged.State = callInfo.State
+    }
+    if callInfo.Actor != (codec.Address{}) {
+        merged.Actor = callInfo.Actor
+    }
+    if callInfo.FunctionName != "" {
+        merged.FunctionName = callInfo.FunctionName
+    }
+    if callInfo.Contract != (codec.Address{}) {
+        merged.Contract = callInfo.Contract
+    }
+    if callInfo.Params != nil {
+        merged.Params = callInfo.Params
+    }
+    if callInfo.Fuel != 0 {
+        merged.Fuel = callInfo.Fuel
+    }
+    if callInfo.Height != 0 {
+        merged.Height = callInfo.Height
+    }
+    if callInfo.Timestamp != 0 {
+        merged.Timestamp = callInfo.Timestamp
+    }
+    if callInfo.ActionID != (ids.ID{}) {
+        merged.ActionID = callInfo.ActionID
+    }
+    if callInfo.Value != 0 {
+        merged.Value = callInfo.Value
+    }
+    if callInfo.CallFlags != 0 {
+        merged.CallFlags = callInfo.CallFlags
+    }
+
+    return c.r.CallContract(ctx, &merged)
+}
@@ -0,0 +1,76 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package runtime
+
+import "context"
+
+// CallFlags is a bitset of capabilities granted to a single contract call,
+// modeled after Neo's callflag package. Host imports check the calling
+// frame's flags before performing the operation they gate and trap if the
+// required flag is missing.
+type CallFlags uint8
+
+const (
+    // ReadStates permits reading contract and account state.
+    ReadStates CallFlags = 1 << iota
+    // WriteStates permits writing contract and account state.
+    WriteStates
+    // AllowCall permits invoking another contract.
+    AllowCall
+    // AllowNotify permits emitting events/notifications.
+    AllowNotify
+    // AllowStateModifications permits privileged state operations beyond a
+    // plain write, such as deploying or retiring a contract.
+    AllowStateModifications
+)
+
+// ReadOnly grants only ReadStates, the most restrictive non-empty flag set.
+const ReadOnly = ReadStates
+
+// All grants every capability.
+const All = ReadStates | WriteStates | AllowCall | AllowNotify | AllowStateModifications
+
+// Has reports whether f grants every flag set in want.
+func (f CallFlags) Has(want CallFlags) bool {
+    return f&want == want
+}
+
+// effectiveFlags returns the flags a call actually runs with. The zero
+// value grants no capabilities at all: a caller that wants anything more
+// than a trapped, no-op call must set CallFlags explicitly (e.g. to All
+// or ReadOnly). This is the default-deny direction a sandboxing bitset
+// needs to take; treating the zero value as All would let any caller that
+// forgets to set CallFlags run unrestricted.
+func (c *CallInfo) effectiveFlags() CallFlags {
+    return c.CallFlags
+}
+
+// callFlagsCtxKey carries the currently executing call's effective flags on
+// ctx, so a nested CallContract invocation (one contract calling another)
+// can AND them with the flags declared at the nested call site.
+type callFlagsCtxKey struct{}
+
+func contextWithCallFlags(ctx context.Context, flags CallFlags) context.Context {
+    return context.WithValue(ctx, callFlagsCtxKey{}, flags)
+}
+
+func callFlagsFromContext(ctx context.Context) (CallFlags, bool) {
+    flags, ok := ctx.Value(callFlagsCtxKey{}).(CallFlags)
+    return flags, ok
+}
+
+// callInfoCtxKey carries the currently executing call's CallInfo on ctx, so
+// a nested CallContract invocation can fold its notifications into the
+// parent's buffer once it commits, and leave the parent's buffer untouched
+// if it reverts.
+type callInfoCtxKey struct{}
+
+func contextWithCallInfo(ctx context.Context, info *CallInfo) context.Context {
+    return context.WithValue(ctx, callInfoCtxKey{}, info)
+}
+
+func callInfoFromContext(ctx context.Context) (*CallInfo, bool) {
+    info, ok := ctx.Value(callInfoCtxKey{}).(*CallInfo)
+    return info, ok
+}
@@ -0,0 +1,92 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package runtime
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/x/contracts/test"
+)
+
+func TestCallFlagsHas(t *testing.T) {
+    require := require.New(t)
+
+    require.True(All.Has(ReadStates))
+    require.True(All.Has(WriteStates))
+    require.True(All.Has(AllowCall))
+    require.True(All.Has(AllowNotify))
+    require.True(All.Has(AllowStateModifications))
+
+    require.True(ReadOnly.Has(ReadStates))
+    require.False(ReadOnly.Has(WriteStates))
+    require.False(ReadOnly.Has(AllowCall))
+}
+
+// TestCallInfoEffectiveFlagsDefaultDeny guards against effectiveFlags
+// reintroducing "0 means All": a CallInfo that never opts into any
+// capability must run with none, not every capability.
+func TestCallInfoEffectiveFlagsDefaultDeny(t *testing.T) {
+    require := require.New(t)
+
+    unset := &CallInfo{}
+    require.Equal(CallFlags(0), unset.effectiveFlags())
+    require.False(unset.effectiveFlags().Has(ReadStates))
+
+    granted := &CallInfo{CallFlags: All}
+    require.Equal(All, granted.effectiveFlags())
+}
+
+func TestCallFlagsNestedComposition(t *testing.T) {
+    require := require.New(t)
+
+    ctx := contextWithCallFlags(context.Background(), ReadOnly)
+    parent, ok := callFlagsFromContext(ctx)
+    require.True(ok)
+
+    // A ReadOnly outer call can never be escalated by a nested call that
+    // declares All: the effective flags are the AND of the two.
+    effective := All & parent
+    require.Equal(ReadOnly, effective)
+    require.False(effective.Has(AllowCall))
+    require.False(effective.Has(WriteStates))
+}
+
+func TestCallContextWithCallFlags(t *testing.T) {
+    require := require.New(t)
+
+    base := NewCallContext(nil, CallInfo{})
+    restricted := base.WithCallFlags(ReadOnly)
+
+    require.Equal(CallFlags(0), base.defaults.CallFlags)
+    require.Equal(ReadOnly, restricted.defaults.CallFlags)
+}
+
+func TestCallContractTrapsNestedCallWithoutAllowCall(t *testing.T) {
+    require := require.New(t)
+
+    cfg, err := NewConfigBuilder().Build()
+    require.NoError(err)
+    rt := NewRuntime(cfg, logging.NoLog{})
+
+    // Simulate being invoked from inside a frame that lacks AllowCall.
+    ctx := contextWithCallFlags(context.Background(), ReadOnly)
+
+    _, err = rt.CallContract(ctx, &CallInfo{
+        State: TestStateManager{
+            ContractManager: NewContractStateManager(test.NewTestDB(), []byte{}),
+        },
+        Contract:     codec.CreateAddress(0, ids.GenerateTestID()),
+        FunctionName: "get_value",
+        Fuel:         1000000,
+    })
+
+    require.Error(err)
+    require.Contains(err.Error(), "AllowCall")
+}
@@ -0,0 +1,115 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package runtime
+
+import "github.com/bytecodealliance/wasmtime-go/v25"
+
+// defaultContractCacheSize is the default byte budget for the in-memory LRU
+// module cache.
+const defaultContractCacheSize = 128 * 1024 * 1024 // 128MiB
+
+// Config bundles the tunables that shape a WasmRuntime.
+type Config struct {
+    ContractCacheSize int
+    Validator         ModuleValidator
+    Cache             CacheStrategy
+
+    // PinnedDir, if set, is the directory PinContract/UnpinContract persist
+    // serialized modules to so they skip recompilation across restarts.
+    PinnedDir string
+    // PinnedSet is eagerly pinned by WarmPinnedModules at startup so the
+    // first call to one of these contracts in a block is already warm.
+    PinnedSet []ContractID
+
+    // MaxNotificationSize caps the data length of a single emitted
+    // notification; 0 means unlimited.
+    MaxNotificationSize int
+    // MaxNotificationsPerCall caps how many notifications a call's buffer
+    // may hold when emit_event runs; 0 means unlimited. Events folded in
+    // from nested calls that already returned count toward it, but a
+    // nested call's own emissions are checked only against its own
+    // buffer, so the total across a call tree is not capped.
+    MaxNotificationsPerCall int
+
+    // MaxIterators caps how many StateIterator handles a single call may
+    // hold open at once via iterator_create_storage_prefix; 0 means
+    // unlimited.
+    MaxIterators int
+
+    // LifecycleHooks run around every CallContract and at each block
+    // boundary (see ResetBlockStats), in registration order.
+    LifecycleHooks []LifecycleHook
+
+    wasmConfig *wasmtime.Config
+}
+
+// ConfigBuilder builds a Config the way every other builder in this
+// package is assembled: defaults first, overridden field by field.
+type ConfigBuilder struct {
+    cfg *Config
+}
+
+func NewConfigBuilder() *ConfigBuilder {
+    wasmConfig := wasmtime.NewConfig()
+    wasmConfig.SetConsumeFuel(true)
+    wasmConfig.SetEpochInterruption(true)
+
+    return &ConfigBuilder{
+        cfg: &Config{
+            ContractCacheSize: defaultContractCacheSize,
+            wasmConfig:        wasmConfig,
+        },
+    }
+}
+
+func (b *ConfigBuilder) WithValidator(validator ModuleValidator) *ConfigBuilder {
+    b.cfg.Validator = validator
+    return b
+}
+
+func (b *ConfigBuilder) WithCache(cache CacheStrategy) *ConfigBuilder {
+    b.cfg.Cache = cache
+    return b
+}
+
+func (b *ConfigBuilder) WithContractCacheSize(size int) *ConfigBuilder {
+    b.cfg.ContractCacheSize = size
+    return b
+}
+
+func (b *ConfigBuilder) WithPinnedDir(dir string) *ConfigBuilder {
+    b.cfg.PinnedDir = dir
+    return b
+}
+
+func (b *ConfigBuilder) WithPinnedSet(ids []ContractID) *ConfigBuilder {
+    b.cfg.PinnedSet = ids
+    return b
+}
+
+func (b *ConfigBuilder) WithMaxNotificationSize(size int) *ConfigBuilder {
+    b.cfg.MaxNotificationSize = size
+    return b
+}
+
+func (b *ConfigBuilder) WithMaxNotificationsPerCall(max int) *ConfigBuilder {
+    b.cfg.MaxNotificationsPerCall = max
+    return b
+}
+
+func (b *ConfigBuilder) WithMaxIterators(max int) *ConfigBuilder {
+    b.cfg.MaxIterators = max
+    return b
+}
+
+// WithLifecycleHook appends hook to the hooks run around every CallContract
+// and at each block boundary. Hooks run in the order they were registered.
+func (b *ConfigBuilder) WithLifecycleHook(hook LifecycleHook) *ConfigBuilder {
+    b.cfg.LifecycleHooks = append(b.cfg.LifecycleHooks, hook)
+    return b
+}
+
+func (b *ConfigBuilder) Build() (*Config, error) {
+    return b.cfg, nil
+}
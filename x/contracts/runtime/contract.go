@@ -42,7 +42,80 @@ type CallInfo struct {
     Timestamp uint64
     ActionID ids.ID
     Value uint64
+    // CallFlags gates which host capabilities this call may use. The zero
+    // value grants no capabilities (default-deny); set it explicitly to
+    // All for an unrestricted call or to a narrower set such as ReadOnly.
+    CallFlags CallFlags
     inst *ContractInstance
+
+    // events buffers the events emitted by this call (and any nested calls)
+    // in execution order, so CallContract can surface them in its
+    // ExecutionResult once the call completes.
+    events []Event
+
+    // iterators holds the live StateIterator handles opened by this call via
+    // iterator_create_storage_prefix. It is lazily allocated so a call that
+    // never touches the iterator host import pays nothing for it.
+    iterators *iteratorRegistry
+}
+
+// appendEvent records an event emitted by this call. It is called from the
+// events host import and is not safe for concurrent use by a single
+// CallInfo, matching the single-threaded execution model of a contract call.
+func (c *CallInfo) appendEvent(ev Event) {
+    c.events = append(c.events, ev)
+}
+
+// foldNestedEvents appends a successful nested call's events onto parent's
+// buffer, in the order the nested call emitted them, so a multi-level call
+// chain's events end up ordered the same way nested logs are ordered within
+// a single Ethereum transaction.
+func foldNestedEvents(parent *CallInfo, events []Event) {
+    parent.events = append(parent.events, events...)
+}
+
+// createIterator registers it under a fresh handle scoped to this call.
+func (c *CallInfo) createIterator(it StateIterator) iteratorHandle {
+    if c.iterators == nil {
+        c.iterators = newIteratorRegistry()
+    }
+    return c.iterators.create(it)
+}
+
+// getIterator looks up a handle previously returned by createIterator.
+func (c *CallInfo) getIterator(h iteratorHandle) (StateIterator, bool) {
+    if c.iterators == nil {
+        return nil, false
+    }
+    return c.iterators.get(h)
+}
+
+// releaseIterator releases a single iterator handle, freeing whatever the
+// underlying state manager held open for the scan.
+func (c *CallInfo) releaseIterator(h iteratorHandle) {
+    if c.iterators == nil {
+        return
+    }
+    c.iterators.release(h)
+}
+
+// liveIteratorCount reports how many iterators this call currently holds
+// open, for enforcing Config.MaxIterators.
+func (c *CallInfo) liveIteratorCount() int {
+    if c.iterators == nil {
+        return 0
+    }
+    return c.iterators.count()
+}
+
+// releaseAllIterators releases every iterator still open on this call. It is
+// called when the call's ephemeral instance is disposed so a guest that
+// forgets to call iterator_release never leaks the underlying state cursor.
+func (c *CallInfo) releaseAllIterators() {
+    if c.iterators == nil {
+        return
+    }
+    c.iterators.releaseAll()
 }
 
 type ContractInstance struct {
@@ -43,6 +43,23 @@ type BlockStats struct {
     ContractCalls    uint64
     AvgExecutionTime uint64 // stored in nanoseconds
     CacheHits        uint64
+    // PinnedHits counts calls served from a pinned module, which never pay
+    // compile cost or compete with the LRU cache for space.
+    PinnedHits uint64
+    // NotificationsEmitted and NotificationBytes count committed
+    // notifications only: a reverted call's buffer is discarded rather
+    // than counted.
+    NotificationsEmitted uint64
+    NotificationBytes    uint64
+    // IteratorNextCalls and IteratorBytesRead count every iterator_next
+    // that returned a pair, charged the same key+value bytes used to
+    // compute its fuel cost.
+    IteratorNextCalls uint64
+    IteratorBytesRead uint64
+    // HookFuelUsed accumulates fuel consumed by LifecycleHook PreCall/
+    // PostCall callbacks, kept separate from TotalFuelUsed so contract fuel
+    // accounting isn't polluted by hook bookkeeping.
+    HookFuelUsed uint64
 }
 
 // EphemeralInstance wraps a ContractInstance with execution tracking
@@ -66,7 +83,7 @@ func NewEphemeralInstance(inst *wasmtime.Instance, store *wasmtime.Store) *Ephem
 }
 
 // Call executes the contract call and tracks statistics
-func (e *EphemeralInstance) Call(ctx context.Context, callInfo *CallInfo) ([]byte, error) {
+func (e *EphemeralInstance) Call(ctx context.Context, callInfo *CallInfo) (*ExecutionResult, error) {
     if e == nil || e.inst == nil || e.store == nil {
         return nil, errors.New("invalid ephemeral instance")
     }
@@ -87,6 +104,12 @@ func (e *EphemeralInstance) Call(ctx context.Context, callInfo *CallInfo) ([]byt
         store:  e.store,
     }
 
+    // callInfo.inst backs ConsumeFuel/AddFuel/RemainingFuel, which host
+    // imports like iterator_next call mid-execution; without it they'd
+    // dereference a nil *ContractInstance the first time a contract
+    // actually ran one.
+    callInfo.inst = contractInst
+
     // Make the call
     result, err := contractInst.call(ctx, callInfo)
     if err != nil {
@@ -98,7 +121,11 @@ func (e *EphemeralInstance) Call(ctx context.Context, callInfo *CallInfo) ([]byt
     e.stats.ExecutionTime = time.Since(startTime)
     e.stats.FuelUsed = startFuel - endFuel
 
-    return result, nil
+    return &ExecutionResult{
+        Return: result,
+        Events: callInfo.events,
+        Stats:  *e.stats,
+    }, nil
 }
 
 // Close ensures cleanup of resources
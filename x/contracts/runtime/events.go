@@ -0,0 +1,237 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package runtime
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/bytecodealliance/wasmtime-go/v25"
+
+	"github.com/ava-labs/hypersdk/codec"
+)
+
+// Event is a single log entry emitted by a contract during execution,
+// modeled after an Ethereum log: a NameHash identifying which event was
+// emitted, an indexed topic list, and an opaque data payload. Events are
+// ordered within a call the way nested logs are ordered within an Ethereum
+// transaction, so they can be folded into a block-level receipt unmodified.
+type Event struct {
+    Contract codec.Address
+    // NameHash is TopicHash(name) for the event name the emitting contract
+    // passed to emit_event. It is distinct from Topics: Topics are the
+    // schema's declared topic fields, decoded positionally by UnpackLog, so
+    // the event name can never collide with one of them or shift their
+    // indices.
+    NameHash [32]byte
+    Topics   [][]byte
+    Data     []byte
+}
+
+// EventFilter narrows a WatchLogs subscription to events emitted by a set of
+// contracts and/or matching a set of event name hashes. A nil/empty field
+// matches everything along that dimension, mirroring go-ethereum's
+// FilterQuery.
+type EventFilter struct {
+    Contracts  []codec.Address
+    NameHashes [][32]byte
+}
+
+// TopicHash indexes an event name the same way emit_event does, so callers
+// can build an EventFilter without recomputing the hash themselves.
+func TopicHash(eventName string) [32]byte {
+    return sha256.Sum256([]byte(eventName))
+}
+
+func (f *EventFilter) matches(ev Event) bool {
+    if len(f.Contracts) > 0 {
+        found := false
+        for _, addr := range f.Contracts {
+            if addr == ev.Contract {
+                found = true
+                break
+            }
+        }
+        if !found {
+            return false
+        }
+    }
+
+    if len(f.NameHashes) == 0 {
+        return true
+    }
+    for _, want := range f.NameHashes {
+        if ev.NameHash == want {
+            return true
+        }
+    }
+    return false
+}
+
+// ExecutionResult is the full outcome of a CallContract invocation: the raw
+// return bytes, the events the call (and any nested calls) emitted, and the
+// execution statistics collected for the call.
+type ExecutionResult struct {
+    Return []byte
+    Events []Event
+    Stats  ExecutionStats
+}
+
+type eventSubscription struct {
+    filter EventFilter
+    ch     chan Event
+}
+
+// EventsModule is the host import module that lets guest contracts emit
+// events back to the node, alongside NewLogModule.
+type EventsModule struct{}
+
+func NewEventsModule() *EventsModule {
+    return &EventsModule{}
+}
+
+func (*EventsModule) Name() string {
+    return "events"
+}
+
+func (m *EventsModule) Register(link *wasmtime.Linker, r *WasmRuntime) error {
+    return link.FuncWrap(m.Name(), "emit_event",
+        func(caller *wasmtime.Caller, namePtr, nameLen, topicsPtr, topicsLen, dataPtr, dataLen int32) error {
+            callInfo := r.getCallInfo(caller)
+            if callInfo == nil {
+                return errors.New("runtime: emit_event called outside a contract call")
+            }
+            if !callInfo.effectiveFlags().Has(AllowNotify) {
+                return errors.New("runtime: emit_event trapped: caller frame lacks AllowNotify")
+            }
+            if max := r.cfg.MaxNotificationsPerCall; max > 0 && len(callInfo.events) >= max {
+                return fmt.Errorf("runtime: emit_event trapped: call already emitted the maximum %d notifications", max)
+            }
+            if max := r.cfg.MaxNotificationSize; max > 0 && int(dataLen) > max {
+                return fmt.Errorf("runtime: emit_event trapped: notification data of %d bytes exceeds the %d byte limit", dataLen, max)
+            }
+
+            mem := caller.GetExport(MemoryName).Memory()
+            raw := mem.UnsafeData(caller)
+
+            name := make([]byte, nameLen)
+            copy(name, raw[namePtr:namePtr+nameLen])
+
+            var topics [][]byte
+            if err := Deserialize(raw[topicsPtr:topicsPtr+topicsLen], &topics); err != nil {
+                return err
+            }
+
+            data := make([]byte, dataLen)
+            copy(data, raw[dataPtr:dataPtr+dataLen])
+
+            callInfo.appendEvent(Event{
+                Contract: callInfo.Contract,
+                NameHash: TopicHash(string(name)),
+                Topics:   topics,
+                Data:     data,
+            })
+            return nil
+        },
+    )
+}
+
+// WatchLogs subscribes to events emitted by future CallContract invocations
+// that match filter. The channel is closed once ctx is done.
+func (r *WasmRuntime) WatchLogs(ctx context.Context, filter EventFilter) (<-chan Event, error) {
+    sub := &eventSubscription{
+        filter: filter,
+        ch:     make(chan Event, 64),
+    }
+
+    r.eventSubsMu.Lock()
+    r.eventSubs = append(r.eventSubs, sub)
+    r.eventSubsMu.Unlock()
+
+    go func() {
+        <-ctx.Done()
+        r.removeEventSub(sub)
+        close(sub.ch)
+    }()
+
+    return sub.ch, nil
+}
+
+func (r *WasmRuntime) removeEventSub(sub *eventSubscription) {
+    r.eventSubsMu.Lock()
+    defer r.eventSubsMu.Unlock()
+    for i, s := range r.eventSubs {
+        if s == sub {
+            r.eventSubs = append(r.eventSubs[:i], r.eventSubs[i+1:]...)
+            return
+        }
+    }
+}
+
+// publishEvents fans committed events out to any live WatchLogs subscribers.
+// It never blocks a subscriber that isn't keeping up with its channel.
+func (r *WasmRuntime) publishEvents(events []Event) {
+    if len(events) == 0 {
+        return
+    }
+
+    r.eventSubsMu.Lock()
+    defer r.eventSubsMu.Unlock()
+    for _, ev := range events {
+        for _, sub := range r.eventSubs {
+            if !sub.filter.matches(ev) {
+                continue
+            }
+            select {
+            case sub.ch <- ev:
+            default:
+            }
+        }
+    }
+}
+
+// UnpackLog decodes ev into out, which must be a pointer to a struct whose
+// fields mirror a generated {Contract}{Event}Event struct: its first len(ev.Topics)
+// fields decode one-for-one, in order, from ev.Topics, and its remaining
+// fields decode together from ev.Data, the same way a contract's Topics and
+// Data schema fields are laid out. eventName identifies the event being
+// decoded for error messages only; the field layout of out is what actually
+// drives decoding.
+func (r *WasmRuntime) UnpackLog(out any, eventName string, ev Event) error {
+    v := reflect.ValueOf(out)
+    if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+        return fmt.Errorf("runtime: UnpackLog: event %q: out must be a pointer to a struct, got %T", eventName, out)
+    }
+    structVal := v.Elem()
+    structType := structVal.Type()
+
+    if len(ev.Topics) > structType.NumField() {
+        return fmt.Errorf("runtime: UnpackLog: event %q has %d topics but out only declares %d fields", eventName, len(ev.Topics), structType.NumField())
+    }
+
+    for i, topic := range ev.Topics {
+        if err := Deserialize(topic, structVal.Field(i).Addr().Interface()); err != nil {
+            return fmt.Errorf("runtime: UnpackLog: event %q: decoding topic %d: %w", eventName, i, err)
+        }
+    }
+
+    if remaining := structType.NumField() - len(ev.Topics); remaining > 0 {
+        dataFields := make([]reflect.StructField, remaining)
+        for i := range dataFields {
+            dataFields[i] = structType.Field(len(ev.Topics) + i)
+        }
+        decodedData := reflect.New(reflect.StructOf(dataFields))
+        if err := Deserialize(ev.Data, decodedData.Interface()); err != nil {
+            return fmt.Errorf("runtime: UnpackLog: event %q: decoding data: %w", eventName, err)
+        }
+        for i := range dataFields {
+            structVal.Field(len(ev.Topics) + i).Set(decodedData.Elem().Field(i))
+        }
+    }
+
+    return nil
+}
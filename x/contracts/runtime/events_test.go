@@ -0,0 +1,246 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package runtime
+
+import (
+    "context"
+    "testing"
+
+    "github.com/ava-labs/avalanchego/ids"
+    "github.com/ava-labs/avalanchego/utils/logging"
+    "github.com/stretchr/testify/require"
+
+    "github.com/ava-labs/hypersdk/codec"
+)
+
+func TestEventFilterMatches(t *testing.T) {
+    require := require.New(t)
+
+    contractA := codec.CreateAddress(0, [32]byte{1})
+    contractB := codec.CreateAddress(0, [32]byte{2})
+    topic := []byte("transfer")
+
+    ev := Event{Contract: contractA, NameHash: TopicHash("Transfer"), Topics: [][]byte{topic}, Data: []byte("payload")}
+
+    t.Run("empty filter matches everything", func(t *testing.T) {
+        f := &EventFilter{}
+        require.True(f.matches(ev))
+    })
+
+    t.Run("contract filter", func(t *testing.T) {
+        f := &EventFilter{Contracts: []codec.Address{contractB}}
+        require.False(f.matches(ev))
+
+        f = &EventFilter{Contracts: []codec.Address{contractA}}
+        require.True(f.matches(ev))
+    })
+
+    t.Run("name hash filter matches NameHash, not a hash over Topics", func(t *testing.T) {
+        f := &EventFilter{NameHashes: [][32]byte{TopicHash("Transfer")}}
+        require.True(f.matches(ev))
+
+        f = &EventFilter{NameHashes: [][32]byte{TopicHash("Mint")}}
+        require.False(f.matches(ev))
+
+        // A filter hashing the raw topic bytes themselves must not
+        // accidentally match: NameHash and Topics are separate fields.
+        f = &EventFilter{NameHashes: [][32]byte{TopicHash(string(topic))}}
+        require.False(f.matches(ev))
+    })
+}
+
+// TestCallInfoFromContextRoundTrip mirrors TestCallFlagsNestedComposition: a
+// nested CallContract invocation must be able to recover the parent
+// CallInfo it was dispatched under so it can fold its notifications in.
+func TestCallInfoFromContextRoundTrip(t *testing.T) {
+    require := require.New(t)
+
+    parent := &CallInfo{}
+    ctx := contextWithCallInfo(context.Background(), parent)
+
+    got, ok := callInfoFromContext(ctx)
+    require.True(ok)
+    require.Same(parent, got)
+
+    _, ok = callInfoFromContext(context.Background())
+    require.False(ok)
+}
+
+// TestTrappedNestedCallDoesNotFoldIntoParent drives a real nested CallContract
+// invocation through the context plumbing CallContract itself uses to
+// detect nesting and fold events, rather than appending to parent.events by
+// hand. The nested call traps (its contract was never registered), so it
+// must never reach the fold step in CallContract, and the parent's buffer
+// must come back exactly as it was.
+func TestTrappedNestedCallDoesNotFoldIntoParent(t *testing.T) {
+    require := require.New(t)
+
+    cfg, err := NewConfigBuilder().Build()
+    require.NoError(err)
+    rt := NewRuntime(cfg, logging.NoLog{})
+
+    parent := &CallInfo{State: newManifestStateManager(), CallFlags: All}
+    parent.appendEvent(Event{Data: []byte("parent-1")})
+
+    ctx := contextWithCallInfo(context.Background(), parent)
+    ctx = contextWithCallFlags(ctx, All)
+
+    _, err = rt.CallContract(ctx, &CallInfo{
+        State:        newManifestStateManager(),
+        Contract:     codec.CreateAddress(0, ids.GenerateTestID()),
+        FunctionName: "get_value",
+        Fuel:         1000000,
+        CallFlags:    All,
+    })
+    require.Error(err)
+
+    // The trapped nested call never reached CallContract's fold step, so
+    // the parent's buffer is untouched by it.
+    require.Equal(1, len(parent.events))
+    require.Equal([]byte("parent-1"), parent.events[0].Data)
+}
+
+// TestFoldNestedEventsAppendsInOrder exercises runtime.go's fold-on-success
+// branch directly: CallContract calls foldNestedEvents once a nested call
+// commits, and this is the only coverage of that branch, since driving it
+// through CallContract itself would need a compiled WASM contract that
+// both succeeds and emits events.
+func TestFoldNestedEventsAppendsInOrder(t *testing.T) {
+    require := require.New(t)
+
+    parent := &CallInfo{}
+    parent.appendEvent(Event{Data: []byte("parent-1")})
+
+    foldNestedEvents(parent, []Event{
+        {Data: []byte("child-1")},
+        {Data: []byte("child-2")},
+    })
+
+    require.Equal(3, len(parent.events))
+    require.Equal([]byte("parent-1"), parent.events[0].Data)
+    require.Equal([]byte("child-1"), parent.events[1].Data)
+    require.Equal([]byte("child-2"), parent.events[2].Data)
+}
+
+func TestConfigBuilderNotificationLimits(t *testing.T) {
+    require := require.New(t)
+
+    cfg, err := NewConfigBuilder().
+        WithMaxNotificationSize(128).
+        WithMaxNotificationsPerCall(4).
+        Build()
+    require.NoError(err)
+    require.Equal(128, cfg.MaxNotificationSize)
+    require.Equal(4, cfg.MaxNotificationsPerCall)
+
+    cfg, err = NewConfigBuilder().Build()
+    require.NoError(err)
+    require.Equal(0, cfg.MaxNotificationSize)
+    require.Equal(0, cfg.MaxNotificationsPerCall)
+}
+
+// TestUnpackLogDecodesTopicsAndData exercises the bug this subsystem
+// shipped with: topic fields must come from ev.Topics, in order, and only
+// the remaining fields should come from ev.Data.
+func TestUnpackLogDecodesTopicsAndData(t *testing.T) {
+    require := require.New(t)
+
+    r := &WasmRuntime{}
+
+    from := codec.CreateAddress(0, [32]byte{1})
+    to := codec.CreateAddress(0, [32]byte{2})
+
+    fromTopic, err := Serialize(from)
+    require.NoError(err)
+    toTopic, err := Serialize(to)
+    require.NoError(err)
+    data, err := Serialize(struct{ Amount uint64 }{Amount: 42})
+    require.NoError(err)
+
+    ev := Event{
+        Topics: [][]byte{fromTopic, toTopic},
+        Data:   data,
+    }
+
+    var decoded struct {
+        From   codec.Address
+        To     codec.Address
+        Amount uint64
+    }
+    require.NoError(r.UnpackLog(&decoded, "Transfer", ev))
+    require.Equal(from, decoded.From)
+    require.Equal(to, decoded.To)
+    require.Equal(uint64(42), decoded.Amount)
+}
+
+// TestEventNameHashDistinctFromTopics guards the wire contract a generated
+// WatchXxx client and UnpackLog both rely on: an event's NameHash
+// identifies it independently of Topics, so an EventFilter matching on
+// NameHash never disturbs UnpackLog's positional decode of Topics/Data.
+func TestEventNameHashDistinctFromTopics(t *testing.T) {
+    require := require.New(t)
+
+    from := codec.CreateAddress(0, [32]byte{1})
+    to := codec.CreateAddress(0, [32]byte{2})
+    fromTopic, err := Serialize(from)
+    require.NoError(err)
+    toTopic, err := Serialize(to)
+    require.NoError(err)
+    data, err := Serialize(struct{ Amount uint64 }{Amount: 42})
+    require.NoError(err)
+
+    ev := Event{
+        Contract: codec.CreateAddress(0, [32]byte{9}),
+        NameHash: TopicHash("Transfer"),
+        Topics:   [][]byte{fromTopic, toTopic},
+        Data:     data,
+    }
+
+    filter := EventFilter{NameHashes: [][32]byte{TopicHash("Transfer")}}
+    require.True(filter.matches(ev))
+
+    r := &WasmRuntime{}
+    var decoded struct {
+        From   codec.Address
+        To     codec.Address
+        Amount uint64
+    }
+    require.NoError(r.UnpackLog(&decoded, "Transfer", ev))
+    require.Equal(from, decoded.From)
+    require.Equal(to, decoded.To)
+    require.Equal(uint64(42), decoded.Amount)
+}
+
+func TestUnpackLogRejectsTooFewFields(t *testing.T) {
+    require := require.New(t)
+
+    r := &WasmRuntime{}
+    topic, err := Serialize(codec.CreateAddress(0, [32]byte{1}))
+    require.NoError(err)
+
+    var decoded struct {
+        Only string
+    }
+    err = r.UnpackLog(&decoded, "Transfer", Event{Topics: [][]byte{topic, topic}})
+    require.Error(err)
+    require.Contains(err.Error(), "Transfer")
+}
+
+func TestBlockStatsNotificationCounters(t *testing.T) {
+    require := require.New(t)
+
+    rt := &WasmRuntime{cfg: &Config{}}
+    rt.blockStats.NotificationsEmitted = 2
+    rt.blockStats.NotificationBytes = 10
+
+    stats := rt.GetBlockStats()
+    require.Equal(uint64(2), stats.NotificationsEmitted)
+    require.Equal(uint64(10), stats.NotificationBytes)
+
+    err := rt.ResetBlockStats(context.Background(), BlockContext{}, BlockContext{})
+    require.NoError(err)
+    stats = rt.GetBlockStats()
+    require.Equal(uint64(0), stats.NotificationsEmitted)
+    require.Equal(uint64(0), stats.NotificationBytes)
+}
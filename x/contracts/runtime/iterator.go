@@ -0,0 +1,212 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package runtime
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "sync/atomic"
+
+    "github.com/bytecodealliance/wasmtime-go/v25"
+
+    "github.com/ava-labs/hypersdk/codec"
+)
+
+// StateIterator is the cursor a RangeIterable StateManager returns for a
+// prefix scan, mirroring avalanchego's database.Iterator: Next advances the
+// cursor and reports whether a pair is available, Key/Value read the
+// current pair, Error reports any fault the scan hit, and Release frees
+// whatever the underlying store held open for it.
+type StateIterator interface {
+    Next() bool
+    Key() []byte
+    Value() []byte
+    Error() error
+    Release()
+}
+
+// RangeIterable is implemented by a StateManager that can open a prefix
+// scan over a contract's storage. A StateManager that doesn't implement it
+// simply can't back iterator_create_storage_prefix.
+type RangeIterable interface {
+    NewRangeIterator(ctx context.Context, contract codec.Address, prefix []byte) (StateIterator, error)
+}
+
+// iteratorHandle identifies one open StateIterator within a single call's
+// registry. A handle is only meaningful for the CallInfo it was created
+// under and for the lifetime of that call.
+type iteratorHandle int32
+
+// iteratorRegistry tracks the live iterators a single call (and any nested
+// calls sharing its CallInfo) has open. It is not safe for concurrent use,
+// matching the single-threaded execution model of a contract call.
+type iteratorRegistry struct {
+    next      iteratorHandle
+    iterators map[iteratorHandle]StateIterator
+}
+
+func newIteratorRegistry() *iteratorRegistry {
+    return &iteratorRegistry{iterators: map[iteratorHandle]StateIterator{}}
+}
+
+func (reg *iteratorRegistry) create(it StateIterator) iteratorHandle {
+    reg.next++
+    reg.iterators[reg.next] = it
+    return reg.next
+}
+
+func (reg *iteratorRegistry) get(h iteratorHandle) (StateIterator, bool) {
+    it, ok := reg.iterators[h]
+    return it, ok
+}
+
+func (reg *iteratorRegistry) release(h iteratorHandle) {
+    if it, ok := reg.iterators[h]; ok {
+        it.Release()
+        delete(reg.iterators, h)
+    }
+}
+
+func (reg *iteratorRegistry) count() int {
+    return len(reg.iterators)
+}
+
+func (reg *iteratorRegistry) releaseAll() {
+    for h, it := range reg.iterators {
+        it.Release()
+        delete(reg.iterators, h)
+    }
+}
+
+// iteratorKV is the wire shape iterator_value packs into guest memory for a
+// single key/value pair, using the same Serialize codec as everything else
+// crossing the host/guest boundary.
+type iteratorKV struct {
+    Key   []byte
+    Value []byte
+}
+
+// IteratorModule is the host import module that lets guest contracts page
+// through large state ranges (e.g. storage prefix scans) by handle instead
+// of materializing the whole result set into linear memory up front.
+type IteratorModule struct{}
+
+func NewIteratorModule() *IteratorModule {
+    return &IteratorModule{}
+}
+
+func (*IteratorModule) Name() string {
+    return "iterator"
+}
+
+func (m *IteratorModule) Register(link *wasmtime.Linker, r *WasmRuntime) error {
+    if err := link.FuncWrap(m.Name(), "iterator_create_storage_prefix",
+        func(caller *wasmtime.Caller, prefixPtr, prefixLen int32) (int32, error) {
+            callInfo := r.getCallInfo(caller)
+            if callInfo == nil {
+                return 0, errors.New("runtime: iterator_create_storage_prefix called outside a contract call")
+            }
+            if !callInfo.effectiveFlags().Has(ReadStates) {
+                return 0, errors.New("runtime: iterator_create_storage_prefix trapped: caller frame lacks ReadStates")
+            }
+            if max := r.cfg.MaxIterators; max > 0 && callInfo.liveIteratorCount() >= max {
+                return 0, fmt.Errorf("runtime: iterator_create_storage_prefix trapped: call already holds the maximum %d live iterators", max)
+            }
+
+            rangeable, ok := callInfo.State.(RangeIterable)
+            if !ok {
+                return 0, errors.New("runtime: iterator_create_storage_prefix trapped: state manager does not support range iteration")
+            }
+
+            mem := caller.GetExport(MemoryName).Memory()
+            raw := mem.UnsafeData(caller)
+            prefix := make([]byte, prefixLen)
+            copy(prefix, raw[prefixPtr:prefixPtr+prefixLen])
+
+            it, err := rangeable.NewRangeIterator(context.Background(), callInfo.Contract, prefix)
+            if err != nil {
+                return 0, err
+            }
+
+            return int32(callInfo.createIterator(it)), nil
+        },
+    ); err != nil {
+        return err
+    }
+
+    if err := link.FuncWrap(m.Name(), "iterator_next",
+        func(caller *wasmtime.Caller, handle int32) (int32, error) {
+            callInfo := r.getCallInfo(caller)
+            if callInfo == nil {
+                return 0, errors.New("runtime: iterator_next called outside a contract call")
+            }
+
+            it, ok := callInfo.getIterator(iteratorHandle(handle))
+            if !ok {
+                return 0, fmt.Errorf("runtime: iterator_next trapped: unknown iterator handle %d", handle)
+            }
+
+            if !it.Next() {
+                return 0, it.Error()
+            }
+
+            cost := uint64(len(it.Key()) + len(it.Value()))
+            if err := callInfo.ConsumeFuel(cost); err != nil {
+                return 0, err
+            }
+            atomic.AddUint64(&r.blockStats.IteratorNextCalls, 1)
+            atomic.AddUint64(&r.blockStats.IteratorBytesRead, cost)
+
+            return 1, nil
+        },
+    ); err != nil {
+        return err
+    }
+
+    if err := link.FuncWrap(m.Name(), "iterator_value",
+        func(caller *wasmtime.Caller, handle int32) (int32, error) {
+            callInfo := r.getCallInfo(caller)
+            if callInfo == nil {
+                return 0, errors.New("runtime: iterator_value called outside a contract call")
+            }
+
+            it, ok := callInfo.getIterator(iteratorHandle(handle))
+            if !ok {
+                return 0, fmt.Errorf("runtime: iterator_value trapped: unknown iterator handle %d", handle)
+            }
+
+            packed, err := Serialize(iteratorKV{Key: it.Key(), Value: it.Value()})
+            if err != nil {
+                return 0, err
+            }
+
+            allocFn := caller.GetExport(AllocName).Func()
+            offsetIntf, err := allocFn.Call(caller, int32(len(packed)))
+            if err != nil {
+                return 0, err
+            }
+            offset := offsetIntf.(int32)
+
+            mem := caller.GetExport(MemoryName).Memory()
+            raw := mem.UnsafeData(caller)
+            copy(raw[offset:], packed)
+
+            return offset, nil
+        },
+    ); err != nil {
+        return err
+    }
+
+    return link.FuncWrap(m.Name(), "iterator_release",
+        func(caller *wasmtime.Caller, handle int32) error {
+            callInfo := r.getCallInfo(caller)
+            if callInfo == nil {
+                return errors.New("runtime: iterator_release called outside a contract call")
+            }
+            callInfo.releaseIterator(iteratorHandle(handle))
+            return nil
+        },
+    )
+}
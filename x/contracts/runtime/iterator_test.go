@@ -0,0 +1,129 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package runtime
+
+import (
+    "errors"
+    "sync"
+    "testing"
+
+    "github.com/stretchr/testify/require"
+)
+
+// fakeIterator is an in-memory StateIterator used to test handle
+// bookkeeping without needing a real StateManager-backed range scan.
+type fakeIterator struct {
+    pairs    [][2]string
+    pos      int
+    released bool
+}
+
+func newFakeIterator(pairs ...[2]string) *fakeIterator {
+    return &fakeIterator{pairs: pairs, pos: -1}
+}
+
+func (f *fakeIterator) Next() bool {
+    f.pos++
+    return f.pos < len(f.pairs)
+}
+
+func (f *fakeIterator) Key() []byte   { return []byte(f.pairs[f.pos][0]) }
+func (f *fakeIterator) Value() []byte { return []byte(f.pairs[f.pos][1]) }
+func (f *fakeIterator) Error() error  { return nil }
+func (f *fakeIterator) Release()      { f.released = true }
+
+func TestCallInfoIteratorRegistry(t *testing.T) {
+    require := require.New(t)
+
+    c := &CallInfo{}
+    require.Equal(0, c.liveIteratorCount())
+
+    it := newFakeIterator([2]string{"k1", "v1"}, [2]string{"k2", "v2"})
+    h := c.createIterator(it)
+    require.Equal(1, c.liveIteratorCount())
+
+    got, ok := c.getIterator(h)
+    require.True(ok)
+    require.Same(it, got)
+
+    require.True(got.Next())
+    require.Equal([]byte("k1"), got.Key())
+    require.Equal([]byte("v1"), got.Value())
+
+    c.releaseIterator(h)
+    require.Equal(0, c.liveIteratorCount())
+    require.True(it.released)
+
+    _, ok = c.getIterator(h)
+    require.False(ok)
+}
+
+func TestCallInfoReleaseAllIterators(t *testing.T) {
+    require := require.New(t)
+
+    c := &CallInfo{}
+    it1 := newFakeIterator()
+    it2 := newFakeIterator()
+    c.createIterator(it1)
+    c.createIterator(it2)
+    require.Equal(2, c.liveIteratorCount())
+
+    // Simulates the ephemeral instance being disposed without the guest
+    // having called iterator_release on either handle.
+    c.releaseAllIterators()
+    require.Equal(0, c.liveIteratorCount())
+    require.True(it1.released)
+    require.True(it2.released)
+}
+
+// TestIteratorHandleIsolation mirrors TestEphemeralInstanceIsolation: each
+// call gets its own CallInfo, so concurrent calls holding iterator handles
+// never see each other's handles or cursor state.
+func TestIteratorHandleIsolation(t *testing.T) {
+    require := require.New(t)
+
+    numConcurrent := 5
+    var wg sync.WaitGroup
+    results := make(chan error, numConcurrent)
+
+    for i := 0; i < numConcurrent; i++ {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            c := &CallInfo{}
+            it := newFakeIterator([2]string{"k", "v"})
+            h := c.createIterator(it)
+
+            if count := c.liveIteratorCount(); count != 1 {
+                results <- errors.New("expected exactly one live iterator on this call's own registry")
+                return
+            }
+            got, ok := c.getIterator(h)
+            if !ok || got != it {
+                results <- errors.New("handle did not resolve back to this call's own iterator")
+                return
+            }
+            c.releaseIterator(h)
+            if !it.released {
+                results <- errors.New("release did not reach the underlying iterator")
+                return
+            }
+            results <- nil
+        }()
+    }
+
+    wg.Wait()
+    close(results)
+    for err := range results {
+        require.NoError(err)
+    }
+}
+
+func TestConfigBuilderMaxIterators(t *testing.T) {
+    require := require.New(t)
+
+    cfg, err := NewConfigBuilder().WithMaxIterators(2).Build()
+    require.NoError(err)
+    require.Equal(2, cfg.MaxIterators)
+}
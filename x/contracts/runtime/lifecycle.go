@@ -0,0 +1,38 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package runtime
+
+import "context"
+
+// BlockContext carries the per-block metadata a LifecycleHook needs at a
+// block boundary, the way Context carries per-call metadata to a contract.
+type BlockContext struct {
+    Height    uint64
+    Timestamp uint64
+}
+
+// LifecycleHook lets a native/system contract observe and influence every
+// CallContract invocation and each block boundary, the way Neo's native
+// contracts hook into OnPersist/PostPersist. Hooks registered via
+// ConfigBuilder.WithLifecycleHook run in registration order, and that order
+// is deterministic across replays.
+type LifecycleHook interface {
+    // PreCall runs before a call's module is instantiated. Returning an
+    // error vetoes the call before any WASM executes. A hook may mutate
+    // callInfo.Fuel or callInfo.CallFlags to influence how the call runs.
+    PreCall(ctx context.Context, callInfo *CallInfo) error
+    // PostCall runs after a call's instance has executed, successfully or
+    // not. Returning an error replaces the call's result with that error.
+    // result is nil if the execution failed. A call that is vetoed or fails
+    // before its module is instantiated does not reach PostCall.
+    PostCall(ctx context.Context, callInfo *CallInfo, result *ExecutionResult, callErr error) error
+    // PrePersist runs once per block, paired with ResetBlockStats, just
+    // before stats for the new block start accumulating. block describes
+    // the block about to start, not the one that just finished.
+    PrePersist(ctx context.Context, block BlockContext) error
+    // PostPersist runs once per block, paired with ResetBlockStats, for the
+    // block that just finished, before its stats are zeroed. block
+    // describes the block that finished, not the one about to start.
+    PostPersist(ctx context.Context, block BlockContext) error
+}
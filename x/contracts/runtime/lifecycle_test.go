@@ -0,0 +1,181 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package runtime
+
+import (
+    "context"
+    "errors"
+    "testing"
+
+    "github.com/ava-labs/avalanchego/ids"
+    "github.com/ava-labs/avalanchego/utils/logging"
+    "github.com/stretchr/testify/require"
+
+    "github.com/ava-labs/hypersdk/codec"
+    "github.com/ava-labs/hypersdk/x/contracts/test"
+)
+
+// recordingHook is a LifecycleHook that records which of its callbacks ran,
+// in order, across every registered instance sharing log.
+type recordingHook struct {
+    name    string
+    log     *[]string
+    preErr  error
+    postErr error
+}
+
+func (h *recordingHook) PreCall(_ context.Context, _ *CallInfo) error {
+    *h.log = append(*h.log, h.name+":PreCall")
+    return h.preErr
+}
+
+func (h *recordingHook) PostCall(_ context.Context, _ *CallInfo, _ *ExecutionResult, _ error) error {
+    *h.log = append(*h.log, h.name+":PostCall")
+    return h.postErr
+}
+
+func (h *recordingHook) PrePersist(_ context.Context, _ BlockContext) error {
+    *h.log = append(*h.log, h.name+":PrePersist")
+    return nil
+}
+
+func (h *recordingHook) PostPersist(_ context.Context, _ BlockContext) error {
+    *h.log = append(*h.log, h.name+":PostPersist")
+    return nil
+}
+
+func TestLifecycleHookOrderingAndVeto(t *testing.T) {
+    require := require.New(t)
+
+    t.Run("PreCall hooks run in registration order", func(t *testing.T) {
+        var log []string
+        first := &recordingHook{name: "first", log: &log}
+        second := &recordingHook{name: "second", log: &log}
+
+        cfg, err := NewConfigBuilder().
+            WithLifecycleHook(first).
+            WithLifecycleHook(second).
+            Build()
+        require.NoError(err)
+        rt := NewRuntime(cfg, logging.NoLog{})
+
+        // The contract doesn't exist, so the call fails once it reaches
+        // state lookup, after both PreCall hooks have already run.
+        _, err = rt.CallContract(context.Background(), &CallInfo{
+            State: TestStateManager{
+                ContractManager: NewContractStateManager(test.NewTestDB(), []byte{}),
+            },
+            Contract:     codec.CreateAddress(0, ids.GenerateTestID()),
+            FunctionName: "get_value",
+            Fuel:         1000000,
+        })
+        require.Error(err)
+        require.Equal([]string{"first:PreCall", "second:PreCall"}, log)
+    })
+
+    t.Run("a vetoing PreCall hook short-circuits before later hooks run", func(t *testing.T) {
+        var log []string
+        veto := &recordingHook{name: "veto", log: &log, preErr: errors.New("rate limited")}
+        never := &recordingHook{name: "never", log: &log}
+
+        cfg, err := NewConfigBuilder().
+            WithLifecycleHook(veto).
+            WithLifecycleHook(never).
+            Build()
+        require.NoError(err)
+        rt := NewRuntime(cfg, logging.NoLog{})
+
+        _, err = rt.CallContract(context.Background(), &CallInfo{
+            State: TestStateManager{
+                ContractManager: NewContractStateManager(test.NewTestDB(), []byte{}),
+            },
+            Contract:     codec.CreateAddress(0, ids.GenerateTestID()),
+            FunctionName: "get_value",
+            Fuel:         1000000,
+        })
+        require.Error(err)
+        require.Contains(err.Error(), "rate limited")
+        // Only the vetoing hook's PreCall ran; the call never reached
+        // module instantiation or the next hook.
+        require.Equal([]string{"veto:PreCall"}, log)
+    })
+}
+
+// callFlagsMutatingHook grants flags from PreCall, exercising the
+// LifecycleHook doc comment's claim that a hook may mutate
+// callInfo.CallFlags to influence how the call runs.
+type callFlagsMutatingHook struct {
+    flags CallFlags
+}
+
+func (h *callFlagsMutatingHook) PreCall(_ context.Context, callInfo *CallInfo) error {
+    callInfo.CallFlags = h.flags
+    return nil
+}
+
+func (*callFlagsMutatingHook) PostCall(context.Context, *CallInfo, *ExecutionResult, error) error {
+    return nil
+}
+
+func (*callFlagsMutatingHook) PrePersist(context.Context, BlockContext) error  { return nil }
+func (*callFlagsMutatingHook) PostPersist(context.Context, BlockContext) error { return nil }
+
+// TestPreCallHookMutatingCallFlagsAffectsManifestEnforcement confirms a
+// PreCall hook that grants CallFlags the caller didn't request actually
+// reaches the manifest's RequiredFlags check, not just a stale pre-hook
+// local that CallContract then ignores.
+func TestPreCallHookMutatingCallFlagsAffectsManifestEnforcement(t *testing.T) {
+    require := require.New(t)
+
+    manager := NewContractStateManager(test.NewTestDB(), []byte{})
+    state := TestStateManager{ContractManager: manager}
+
+    ctx := context.Background()
+    contract := codec.CreateAddress(0, ids.GenerateTestID())
+    contractID := ContractID(ids.GenerateTestID().Bytes())
+    require.NoError(manager.SetAccountContract(ctx, contract, contractID))
+    require.NoError(manager.SetManifest(ctx, contractID, Manifest{
+        Functions:     []ManifestFunction{{Name: "get_value"}},
+        RequiredFlags: ReadStates,
+    }))
+
+    cfg, err := NewConfigBuilder().
+        WithLifecycleHook(&callFlagsMutatingHook{flags: ReadStates}).
+        Build()
+    require.NoError(err)
+    rt := NewRuntime(cfg, logging.NoLog{})
+
+    // CallFlags is intentionally left at the zero value: only the PreCall
+    // hook grants ReadStates.
+    _, err = rt.CallContract(ctx, &CallInfo{
+        State:        state,
+        Contract:     contract,
+        FunctionName: "get_value",
+        Fuel:         1000000,
+    })
+    require.Error(err)
+    require.NotContains(err.Error(), "lacks the flags")
+}
+
+func TestPostPersistFiresOncePerResetBlockStats(t *testing.T) {
+    require := require.New(t)
+
+    var log []string
+    hook := &recordingHook{name: "h", log: &log}
+
+    cfg, err := NewConfigBuilder().WithLifecycleHook(hook).Build()
+    require.NoError(err)
+    rt := NewRuntime(cfg, logging.NoLog{})
+
+    require.NoError(rt.ResetBlockStats(context.Background(), BlockContext{}, BlockContext{Height: 1}))
+    require.NoError(rt.ResetBlockStats(context.Background(), BlockContext{Height: 1}, BlockContext{Height: 2}))
+
+    postPersistCount := 0
+    for _, entry := range log {
+        if entry == "h:PostPersist" {
+            postPersistCount++
+        }
+    }
+    require.Equal(2, postPersistCount)
+}
@@ -0,0 +1,163 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/bytecodealliance/wasmtime-go/v25"
+
+	"github.com/ava-labs/avalanchego/database"
+
+	"github.com/ava-labs/hypersdk/codec"
+)
+
+// Manifest declares everything a contract's WASM module is permitted to
+// do, shipped alongside its bytecode so an operator can sandbox untrusted
+// code the way Neo's NEF+manifest model does: exported entrypoints, the
+// host imports it may use, the CallFlags it requires, and which contracts
+// it may call.
+type Manifest struct {
+    // Functions lists every entrypoint the contract exports, with its
+    // parameter/return ABI. CallContract refuses any FunctionName not
+    // present here.
+    Functions []ManifestFunction `json:"functions"`
+    // AllowedImports lists the "module.name" host imports the contract may
+    // use, e.g. "events.emit_event". Anything else found in the module's
+    // import section fails validation.
+    AllowedImports []string `json:"allowedImports"`
+    // RequiredFlags are the CallFlags every invocation of this contract
+    // must be granted.
+    RequiredFlags CallFlags `json:"requiredFlags"`
+    // AllowedCallees restricts which contracts this one may invoke via a
+    // cross-contract call. An empty list permits any callee.
+    AllowedCallees []codec.Address `json:"allowedCallees,omitempty"`
+}
+
+// ManifestFunction describes one declared entrypoint's ABI.
+type ManifestFunction struct {
+    Name    string          `json:"name"`
+    Params  []ManifestField `json:"params"`
+    Returns []ManifestField `json:"returns"`
+}
+
+// ManifestField is a single named, typed parameter or return value.
+type ManifestField struct {
+    Name string `json:"name"`
+    Type string `json:"type"`
+}
+
+// HasFunction reports whether name is a declared entrypoint.
+func (m *Manifest) HasFunction(name string) bool {
+    for _, fn := range m.Functions {
+        if fn.Name == name {
+            return true
+        }
+    }
+    return false
+}
+
+func (m *Manifest) allowsImport(module, name string) bool {
+    full := module + "." + name
+    for _, allowed := range m.AllowedImports {
+        if allowed == full {
+            return true
+        }
+    }
+    return false
+}
+
+// allowsCallee reports whether this contract may invoke callee. An empty
+// AllowedCallees permits any callee.
+func (m *Manifest) allowsCallee(callee codec.Address) bool {
+    if len(m.AllowedCallees) == 0 {
+        return true
+    }
+    for _, allowed := range m.AllowedCallees {
+        if allowed == callee {
+            return true
+        }
+    }
+    return false
+}
+
+// lookupManifest returns the manifest stored for id, or nil if state does
+// not implement ManifestStore or has none stored for id. Any other error
+// (a corrupt record, a store I/O failure) is propagated rather than
+// treated as "no manifest": swallowing it would silently disable
+// function/flag enforcement for the contract instead of failing the call
+// closed.
+func lookupManifest(ctx context.Context, state StateManager, id []byte) (*Manifest, error) {
+    store, ok := state.(ManifestStore)
+    if !ok {
+        return nil, nil
+    }
+    manifest, err := store.GetManifest(ctx, id)
+    if errors.Is(err, database.ErrNotFound) {
+        return nil, nil
+    }
+    if err != nil {
+        return nil, fmt.Errorf("runtime: failed to look up manifest: %w", err)
+    }
+    return manifest, nil
+}
+
+// ManifestStore is implemented by a StateManager that can persist contract
+// manifests next to bytecode. A StateManager that doesn't implement it
+// simply opts out of manifest-gated validation and enforcement.
+type ManifestStore interface {
+    GetManifest(ctx context.Context, contractID ContractID) (*Manifest, error)
+    SetManifest(ctx context.Context, contractID ContractID, manifest Manifest) error
+}
+
+// ManifestValidator is implemented by a ModuleValidator that also gates a
+// contract's declared manifest before its module is put into the cache.
+type ManifestValidator interface {
+    ModuleValidator
+    ValidateManifest(ctx context.Context, wasm []byte, manifest Manifest) error
+}
+
+// DefaultManifestValidator rejects any module import or export not
+// declared in its manifest. Its ValidateModule is a no-op: it only has an
+// opinion once a manifest is available to check against.
+type DefaultManifestValidator struct{}
+
+func NewDefaultManifestValidator() *DefaultManifestValidator {
+    return &DefaultManifestValidator{}
+}
+
+func (*DefaultManifestValidator) ValidateModule(context.Context, []byte) error {
+    return nil
+}
+
+func (*DefaultManifestValidator) ValidateManifest(_ context.Context, wasm []byte, manifest Manifest) error {
+    engine := wasmtime.NewEngine()
+    mod, err := wasmtime.NewModule(engine, wasm)
+    if err != nil {
+        return fmt.Errorf("manifest: failed to parse module: %w", err)
+    }
+
+    for _, imp := range mod.Imports() {
+        name := ""
+        if imp.Name() != nil {
+            name = *imp.Name()
+        }
+        if !manifest.allowsImport(imp.Module(), name) {
+            return fmt.Errorf("manifest: import %s.%s is not declared", imp.Module(), name)
+        }
+    }
+
+    for _, exp := range mod.Exports() {
+        if exp.Name() == AllocName || exp.Name() == MemoryName {
+            continue
+        }
+        if !manifest.HasFunction(exp.Name()) {
+            return fmt.Errorf("manifest: export %s is not a declared function", exp.Name())
+        }
+    }
+
+    return nil
+}
@@ -0,0 +1,49 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package runtime
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+)
+
+// manifestKeySuffix distinguishes a manifest's storage key from the
+// ContractID it describes, so GetManifest/SetManifest can ride the same
+// GetContractBytes/SetContractBytes code path ContractStateManager already
+// uses to persist compiled bytecode: the manifest lives right next to it,
+// under a derived key.
+var manifestKeySuffix = []byte(":manifest")
+
+func manifestContractID(contractID ContractID) ContractID {
+    id := make(ContractID, 0, len(contractID)+len(manifestKeySuffix))
+    id = append(id, contractID...)
+    id = append(id, manifestKeySuffix...)
+    return id
+}
+
+// GetManifest returns the manifest persisted for contractID, or an error if
+// none has been stored. It satisfies ManifestStore, so every
+// ContractStateManager-backed StateManager gets manifest-gated validation
+// and enforcement for free.
+func (c *ContractStateManager) GetManifest(ctx context.Context, contractID ContractID) (*Manifest, error) {
+    raw, err := c.GetContractBytes(ctx, manifestContractID(contractID))
+    if err != nil {
+        return nil, err
+    }
+    var manifest Manifest
+    if err := json.Unmarshal(raw, &manifest); err != nil {
+        return nil, fmt.Errorf("manifest: failed to decode manifest for contract %x: %w", contractID, err)
+    }
+    return &manifest, nil
+}
+
+// SetManifest persists manifest next to contractID's compiled bytecode.
+func (c *ContractStateManager) SetManifest(ctx context.Context, contractID ContractID, manifest Manifest) error {
+    raw, err := json.Marshal(manifest)
+    if err != nil {
+        return fmt.Errorf("manifest: failed to encode manifest for contract %x: %w", contractID, err)
+    }
+    return c.SetContractBytes(ctx, manifestContractID(contractID), raw)
+}
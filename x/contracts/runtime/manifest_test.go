@@ -0,0 +1,258 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package runtime
+
+import (
+    "context"
+    "errors"
+    "testing"
+
+    "github.com/ava-labs/avalanchego/database"
+    "github.com/ava-labs/avalanchego/ids"
+    "github.com/ava-labs/avalanchego/utils/logging"
+    "github.com/stretchr/testify/require"
+
+    "github.com/ava-labs/hypersdk/codec"
+    "github.com/ava-labs/hypersdk/state"
+    "github.com/ava-labs/hypersdk/x/contracts/test"
+)
+
+func TestManifestHasFunction(t *testing.T) {
+    require := require.New(t)
+
+    m := &Manifest{Functions: []ManifestFunction{{Name: "get_value"}}}
+    require.True(m.HasFunction("get_value"))
+    require.False(m.HasFunction("set_value"))
+}
+
+func TestManifestAllowsImport(t *testing.T) {
+    require := require.New(t)
+
+    m := &Manifest{AllowedImports: []string{"events.emit_event"}}
+    require.True(m.allowsImport("events", "emit_event"))
+    require.False(m.allowsImport("state", "get"))
+}
+
+func TestManifestAllowsCallee(t *testing.T) {
+    require := require.New(t)
+
+    a := codec.CreateAddress(0, ids.GenerateTestID())
+    b := codec.CreateAddress(0, ids.GenerateTestID())
+
+    open := &Manifest{}
+    require.True(open.allowsCallee(a))
+    require.True(open.allowsCallee(b))
+
+    restricted := &Manifest{AllowedCallees: []codec.Address{a}}
+    require.True(restricted.allowsCallee(a))
+    require.False(restricted.allowsCallee(b))
+}
+
+// contractStateManagerWithBalance adapts a *ContractStateManager into a
+// full StateManager by bolting on no-op balance handling, so tests can
+// exercise lookupManifest against the real manifest storage instead of a
+// synthetic double.
+type contractStateManagerWithBalance struct {
+    *ContractStateManager
+}
+
+func (contractStateManagerWithBalance) GetBalance(context.Context, codec.Address) (uint64, error) {
+    return 0, nil
+}
+
+func (contractStateManagerWithBalance) TransferBalance(context.Context, codec.Address, codec.Address, uint64) error {
+    return nil
+}
+
+// TestContractStateManagerPersistsManifest confirms ContractStateManager
+// itself — the StateManager every non-test caller actually uses — stores
+// and retrieves manifests next to the contract's bytecode, and that
+// lookupManifest resolves it through the real manager, not just a test
+// double.
+func TestContractStateManagerPersistsManifest(t *testing.T) {
+    require := require.New(t)
+    ctx := context.Background()
+
+    manager := contractStateManagerWithBalance{NewContractStateManager(test.NewTestDB(), []byte{})}
+    contractID := ContractID(ids.GenerateTestID().Bytes())
+
+    absent, err := lookupManifest(ctx, manager, contractID)
+    require.NoError(err)
+    require.Nil(absent)
+
+    manifest := Manifest{
+        Functions:     []ManifestFunction{{Name: "get_value"}},
+        RequiredFlags: ReadStates,
+    }
+    require.NoError(manager.SetManifest(ctx, contractID, manifest))
+
+    got, err := manager.GetManifest(ctx, contractID)
+    require.NoError(err)
+    require.Equal(manifest, *got)
+
+    found, err := lookupManifest(ctx, manager, contractID)
+    require.NoError(err)
+    require.Equal(manifest, *found)
+}
+
+// failingManifestStore is a ManifestStore double that always returns a
+// non-ErrNotFound failure, so lookupManifest's not-found/real-error split
+// can be checked without a real store.
+type failingManifestStore struct {
+    StateManager
+    err error
+}
+
+func (f failingManifestStore) GetManifest(context.Context, ContractID) (*Manifest, error) {
+    return nil, f.err
+}
+
+func (f failingManifestStore) SetManifest(context.Context, ContractID, Manifest) error {
+    return errors.New("failingManifestStore: not implemented")
+}
+
+// TestLookupManifestDistinguishesNotFoundFromRealErrors confirms a corrupt
+// record or store I/O failure fails the call closed instead of being
+// silently treated the same as "no manifest stored".
+func TestLookupManifestDistinguishesNotFoundFromRealErrors(t *testing.T) {
+    require := require.New(t)
+    ctx := context.Background()
+
+    t.Run("database.ErrNotFound is treated as no manifest", func(t *testing.T) {
+        store := failingManifestStore{err: database.ErrNotFound}
+        manifest, err := lookupManifest(ctx, store, ContractID{1})
+        require.NoError(err)
+        require.Nil(manifest)
+    })
+
+    t.Run("any other error is propagated", func(t *testing.T) {
+        store := failingManifestStore{err: errors.New("decode failure")}
+        manifest, err := lookupManifest(ctx, store, ContractID{1})
+        require.Error(err)
+        require.Nil(manifest)
+        require.Contains(err.Error(), "decode failure")
+    })
+}
+
+// manifestStateManager is a minimal StateManager + ManifestStore double
+// that only answers the calls CallContract's cross-call manifest check
+// needs: resolving a contract address to a ContractID and a manifest.
+type manifestStateManager struct {
+    contracts map[codec.Address]ContractID
+    manifests map[string]*Manifest
+}
+
+func newManifestStateManager() *manifestStateManager {
+    return &manifestStateManager{
+        contracts: map[codec.Address]ContractID{},
+        manifests: map[string]*Manifest{},
+    }
+}
+
+func (m *manifestStateManager) setContract(addr codec.Address, id ContractID, manifest *Manifest) {
+    m.contracts[addr] = id
+    m.manifests[string(id)] = manifest
+}
+
+func (m *manifestStateManager) GetBalance(context.Context, codec.Address) (uint64, error) {
+    return 0, nil
+}
+
+func (m *manifestStateManager) TransferBalance(context.Context, codec.Address, codec.Address, uint64) error {
+    return nil
+}
+
+func (m *manifestStateManager) GetContractState(codec.Address) state.Mutable {
+    return nil
+}
+
+func (m *manifestStateManager) GetAccountContract(_ context.Context, account codec.Address) (ContractID, error) {
+    id, ok := m.contracts[account]
+    if !ok {
+        return nil, errors.New("manifestStateManager: no contract for account")
+    }
+    return id, nil
+}
+
+func (m *manifestStateManager) GetContractBytes(context.Context, ContractID) ([]byte, error) {
+    return nil, errors.New("manifestStateManager: not implemented")
+}
+
+func (m *manifestStateManager) NewAccountWithContract(context.Context, ContractID, []byte) (codec.Address, error) {
+    return codec.Address{}, errors.New("manifestStateManager: not implemented")
+}
+
+func (m *manifestStateManager) SetAccountContract(context.Context, codec.Address, ContractID) error {
+    return errors.New("manifestStateManager: not implemented")
+}
+
+func (m *manifestStateManager) SetContractBytes(context.Context, ContractID, []byte) error {
+    return errors.New("manifestStateManager: not implemented")
+}
+
+func (m *manifestStateManager) GetManifest(_ context.Context, contractID ContractID) (*Manifest, error) {
+    manifest, ok := m.manifests[string(contractID)]
+    if !ok {
+        return nil, errors.New("manifestStateManager: no manifest for contract")
+    }
+    return manifest, nil
+}
+
+func (m *manifestStateManager) SetManifest(_ context.Context, contractID ContractID, manifest Manifest) error {
+    m.manifests[string(contractID)] = &manifest
+    return nil
+}
+
+// TestCallContractEnforcesAllowedCallees drives a real nested call (via the
+// same callInfoFromContext plumbing CallContract uses to fold events) to
+// confirm a parent's manifest.AllowedCallees is actually checked, not just
+// declared.
+func TestCallContractEnforcesAllowedCallees(t *testing.T) {
+    require := require.New(t)
+
+    cfg, err := NewConfigBuilder().Build()
+    require.NoError(err)
+    rt := NewRuntime(cfg, logging.NoLog{})
+
+    parentAddr := codec.CreateAddress(0, ids.GenerateTestID())
+    parentContractID := ContractID(ids.GenerateTestID().Bytes())
+    allowedCallee := codec.CreateAddress(0, ids.GenerateTestID())
+    disallowedCallee := codec.CreateAddress(0, ids.GenerateTestID())
+
+    state := newManifestStateManager()
+    state.setContract(parentAddr, parentContractID, &Manifest{
+        AllowedCallees: []codec.Address{allowedCallee},
+    })
+
+    parent := &CallInfo{State: state, Contract: parentAddr, CallFlags: All}
+    ctx := contextWithCallInfo(context.Background(), parent)
+    ctx = contextWithCallFlags(ctx, All)
+
+    t.Run("callee not in AllowedCallees is trapped", func(t *testing.T) {
+        _, err := rt.CallContract(ctx, &CallInfo{
+            State:        state,
+            Contract:     disallowedCallee,
+            FunctionName: "get_value",
+            Fuel:         1000000,
+            CallFlags:    All,
+        })
+        require.Error(err)
+        require.Contains(err.Error(), "does not allow calling")
+    })
+
+    t.Run("callee in AllowedCallees proceeds past the manifest check", func(t *testing.T) {
+        _, err := rt.CallContract(ctx, &CallInfo{
+            State:        state,
+            Contract:     allowedCallee,
+            FunctionName: "get_value",
+            Fuel:         1000000,
+            CallFlags:    All,
+        })
+        // The callee itself has no account contract registered, so the call
+        // still fails, but past the AllowedCallees check: the error must not
+        // mention the manifest at all.
+        require.Error(err)
+        require.NotContains(err.Error(), "does not allow calling")
+    })
+}
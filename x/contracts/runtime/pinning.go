@@ -0,0 +1,153 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"sync"
+
+	"github.com/bytecodealliance/wasmtime-go/v25"
+)
+
+// engineFingerprint identifies the wasmtime build a serialized module was
+// compiled with, so a PinnedStore never deserializes a module produced by an
+// incompatible engine. wasmtime.Module.Deserialize already checks this
+// itself, but keying the on-disk path by it lets stale files on an upgraded
+// node be ignored instead of erroring.
+func engineFingerprint() string {
+    version := "unknown"
+    if info, ok := debug.ReadBuildInfo(); ok {
+        for _, dep := range info.Deps {
+            if dep.Path == "github.com/bytecodealliance/wasmtime-go/v25" {
+                version = dep.Version
+                break
+            }
+        }
+    }
+    return version
+}
+
+// PinnedStore persists compiled modules to disk keyed by ContractID and
+// engine fingerprint, the way wasmd's pinned-code store avoids recompiling
+// hot system contracts on every cold start.
+type PinnedStore struct {
+    dir    string
+    engine *wasmtime.Engine
+}
+
+func NewPinnedStore(dir string, engine *wasmtime.Engine) *PinnedStore {
+    return &PinnedStore{dir: dir, engine: engine}
+}
+
+func (s *PinnedStore) path(id ContractID) string {
+    return filepath.Join(s.dir, engineFingerprint(), fmt.Sprintf("%x.module", []byte(id)))
+}
+
+// Get loads a previously pinned module from disk, returning false if none is
+// pinned for id under the current engine fingerprint.
+func (s *PinnedStore) Get(id ContractID) (*wasmtime.Module, bool) {
+    bytes, err := os.ReadFile(s.path(id))
+    if err != nil {
+        return nil, false
+    }
+
+    mod, err := wasmtime.NewModuleDeserialize(s.engine, bytes)
+    if err != nil {
+        return nil, false
+    }
+    return mod, true
+}
+
+// Put serializes mod and persists it to disk keyed by id.
+func (s *PinnedStore) Put(id ContractID, mod *wasmtime.Module) error {
+    bytes, err := mod.Serialize()
+    if err != nil {
+        return fmt.Errorf("failed to serialize pinned module: %w", err)
+    }
+
+    path := s.path(id)
+    if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+        return fmt.Errorf("failed to create pinned store directory: %w", err)
+    }
+    return os.WriteFile(path, bytes, 0o644)
+}
+
+// Delete removes a pinned module from disk, if any.
+func (s *PinnedStore) Delete(id ContractID) error {
+    err := os.Remove(s.path(id))
+    if err != nil && !os.IsNotExist(err) {
+        return err
+    }
+    return nil
+}
+
+// PinContract compiles (if necessary) and pins id so it never pays compile
+// cost or LRU eviction again: its module is kept warm in memory and
+// persisted to the PinnedStore for fast recovery across restarts.
+func (r *WasmRuntime) PinContract(ctx context.Context, callInfo *CallInfo, id ContractID) error {
+    if r.cfg.PinnedDir == "" {
+        return fmt.Errorf("runtime: PinnedDir is not configured")
+    }
+
+    mod, err := r.getModule(ctx, callInfo, id)
+    if err != nil {
+        return fmt.Errorf("failed to compile contract for pinning: %w", err)
+    }
+
+    if err := r.pinnedStore().Put(id, mod); err != nil {
+        return fmt.Errorf("failed to persist pinned module: %w", err)
+    }
+
+    r.pinnedMu.Lock()
+    r.pinnedModules[string(id)] = mod
+    r.pinnedMu.Unlock()
+
+    return nil
+}
+
+// UnpinContract removes id from the pinned store and the in-memory pinned
+// set, letting it fall back to the normal LRU cache.
+func (r *WasmRuntime) UnpinContract(ctx context.Context, id ContractID) error {
+    r.pinnedMu.Lock()
+    delete(r.pinnedModules, string(id))
+    r.pinnedMu.Unlock()
+
+    if r.cfg.PinnedDir == "" {
+        return nil
+    }
+    return r.pinnedStore().Delete(id)
+}
+
+func (r *WasmRuntime) pinnedStore() *PinnedStore {
+    r.pinnedOnce.Do(func() {
+        r.pinned = NewPinnedStore(r.cfg.PinnedDir, r.engine)
+    })
+    return r.pinned
+}
+
+// WarmPinnedModules eagerly compiles (or loads from the PinnedStore) and pins
+// every contract in cfg.PinnedSet so the first call to one of them in a block
+// does not pay compile cost. It creates no instances: every call still gets
+// its own ephemeral instance. It should be called once during node startup.
+func (r *WasmRuntime) WarmPinnedModules(ctx context.Context, callInfo *CallInfo) error {
+    for _, id := range r.cfg.PinnedSet {
+        if err := r.PinContract(ctx, callInfo, id); err != nil {
+            return fmt.Errorf("failed to warm pinned contract %x: %w", []byte(id), err)
+        }
+    }
+    return nil
+}
+
+// pinningState is embedded in WasmRuntime to keep the pinning fields grouped
+// in one place near their implementation.
+type pinningState struct {
+    pinnedOnce    sync.Once
+    pinned        *PinnedStore
+    pinnedMu      sync.Mutex
+    pinnedModules map[string]*wasmtime.Module
+}
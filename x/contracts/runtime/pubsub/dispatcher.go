@@ -0,0 +1,32 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package pubsub lets WasmRuntime.CallContract be dispatched to a pool of
+// worker processes instead of running in-process, for horizontal scaling of
+// contract execution within a single block. It is only safe to use for
+// calls the scheduler has already marked conflict-free, since a Dispatcher
+// gives no ordering guarantees across concurrently submitted calls.
+package pubsub
+
+import (
+	"context"
+
+	"github.com/ava-labs/hypersdk/x/contracts/runtime"
+)
+
+// Result is the outcome of a dispatched call, returned over the channel
+// Submit hands back. Exactly one Result is sent per Submit call.
+type Result struct {
+    Return []byte
+    Events []runtime.Event
+    Stats  runtime.ExecutionStats
+    Err    error
+}
+
+// Dispatcher hands a CallInfo off for execution, locally or remotely, and
+// reports the outcome asynchronously.
+type Dispatcher interface {
+    // Submit schedules call for execution and returns a channel that
+    // receives exactly one Result once it completes.
+    Submit(ctx context.Context, call *runtime.CallInfo) (<-chan Result, error)
+}
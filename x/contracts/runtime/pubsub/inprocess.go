@@ -0,0 +1,43 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package pubsub
+
+import (
+	"context"
+
+	"github.com/ava-labs/hypersdk/x/contracts/runtime"
+)
+
+// InProcessDispatcher runs every call on the local WasmRuntime, preserving
+// today's execution model. It is the default Dispatcher and the one to fall
+// back to when no worker pool is configured.
+type InProcessDispatcher struct {
+    runtime *runtime.WasmRuntime
+}
+
+func NewInProcessDispatcher(rt *runtime.WasmRuntime) *InProcessDispatcher {
+    return &InProcessDispatcher{runtime: rt}
+}
+
+func (d *InProcessDispatcher) Submit(ctx context.Context, call *runtime.CallInfo) (<-chan Result, error) {
+    ch := make(chan Result, 1)
+
+    go func() {
+        defer close(ch)
+
+        res, err := d.runtime.CallContract(ctx, call)
+        if err != nil {
+            ch <- Result{Err: err}
+            return
+        }
+
+        ch <- Result{
+            Return: res.Return,
+            Events: res.Events,
+            Stats:  res.Stats,
+        }
+    }()
+
+    return ch, nil
+}
@@ -0,0 +1,240 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package pubsub
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/ava-labs/hypersdk/x/contracts/runtime"
+)
+
+// callRecord is the wire form of a CallInfo submitted to the work stream.
+// It omits State, which a worker reaches through a redisStateManager proxy
+// back to the producer instead of serializing directly.
+type callRecord struct {
+    ID           string
+    Actor        []byte
+    FunctionName string
+    Contract     []byte
+    Params       []byte
+    Fuel         uint64
+    Height       uint64
+    Timestamp    uint64
+    ActionID     []byte
+    Value        uint64
+    // CallFlags is the submitter's original CallInfo.CallFlags. A scheduler
+    // marking a call conflict-free says nothing about what capabilities it
+    // should run with, so this must ride along rather than being re-granted
+    // by the worker.
+    CallFlags runtime.CallFlags
+}
+
+type resultRecord struct {
+    ID     string
+    Result Result
+}
+
+// RedisDispatcher submits calls to a Redis stream consumed by a pool of
+// worker processes, each running their own WasmRuntime, and collects
+// results from a reply stream keyed by request id. It lets contract
+// execution for a single block scale horizontally across processes, as
+// long as the calls submitted are already known to be conflict-free.
+type RedisDispatcher struct {
+    client      *redis.Client
+    workStream  string
+    replyStream string
+    group       string
+
+    // requestKey/responseKey back the StateManager RPC shim handed to
+    // workers so they can read/write the producer's state.
+    requestKey  string
+    responseKey string
+
+    // pendingMu guards pending: Submit and PollReplies run concurrently
+    // whenever a block producer is dispatching many in-flight calls.
+    pendingMu sync.Mutex
+    pending   map[string]chan Result
+}
+
+// NewRedisDispatcher creates the consumer group backing workStream if it
+// does not already exist and returns a dispatcher ready to submit calls.
+func NewRedisDispatcher(ctx context.Context, client *redis.Client, workStream, replyStream, group string) (*RedisDispatcher, error) {
+    err := client.XGroupCreateMkStream(ctx, workStream, group, "$").Err()
+    if err != nil && err.Error() != "BUSYGROUP Consumer Group name already exists" {
+        return nil, fmt.Errorf("pubsub: failed to create consumer group: %w", err)
+    }
+
+    return &RedisDispatcher{
+        client:      client,
+        workStream:  workStream,
+        replyStream: replyStream,
+        group:       group,
+        requestKey:  workStream + ".state.req",
+        responseKey: workStream + ".state.resp",
+        pending:     make(map[string]chan Result),
+    }, nil
+}
+
+func (d *RedisDispatcher) Submit(ctx context.Context, call *runtime.CallInfo) (<-chan Result, error) {
+    record := callRecord{
+        ID:           newRequestID(),
+        Actor:        call.Actor[:],
+        FunctionName: call.FunctionName,
+        Contract:     call.Contract[:],
+        Params:       call.Params,
+        Fuel:         call.Fuel,
+        Height:       call.Height,
+        Timestamp:    call.Timestamp,
+        ActionID:     call.ActionID[:],
+        Value:        call.Value,
+        CallFlags:    call.CallFlags,
+    }
+
+    payload, err := runtime.Serialize(record)
+    if err != nil {
+        return nil, fmt.Errorf("pubsub: failed to serialize call: %w", err)
+    }
+
+    ch := make(chan Result, 1)
+    d.pendingMu.Lock()
+    d.pending[record.ID] = ch
+    d.pendingMu.Unlock()
+
+    if err := d.client.XAdd(ctx, &redis.XAddArgs{
+        Stream: d.workStream,
+        Values: map[string]any{"payload": payload},
+    }).Err(); err != nil {
+        d.pendingMu.Lock()
+        delete(d.pending, record.ID)
+        d.pendingMu.Unlock()
+        return nil, fmt.Errorf("pubsub: failed to submit call: %w", err)
+    }
+
+    return ch, nil
+}
+
+// PollReplies reads completed results off the reply stream and delivers
+// them to the channel Submit returned for that request. The block producer
+// runs this in a loop alongside submitting work.
+func (d *RedisDispatcher) PollReplies(ctx context.Context, lastID string) (string, error) {
+    streams, err := d.client.XRead(ctx, &redis.XReadArgs{
+        Streams: []string{d.replyStream, lastID},
+        Block:   time.Second,
+        Count:   64,
+    }).Result()
+    if err == redis.Nil {
+        return lastID, nil
+    }
+    if err != nil {
+        return lastID, fmt.Errorf("pubsub: failed to read replies: %w", err)
+    }
+
+    for _, stream := range streams {
+        for _, msg := range stream.Messages {
+            lastID = msg.ID
+            payload, ok := msg.Values["payload"].(string)
+            if !ok {
+                continue
+            }
+
+            var rec resultRecord
+            if err := runtime.Deserialize([]byte(payload), &rec); err != nil {
+                continue
+            }
+
+            d.pendingMu.Lock()
+            ch, ok := d.pending[rec.ID]
+            if ok {
+                delete(d.pending, rec.ID)
+            }
+            d.pendingMu.Unlock()
+            if ok {
+                ch <- rec.Result
+                close(ch)
+            }
+        }
+    }
+
+    return lastID, nil
+}
+
+// ServeStateRequests reads stateRequests submitted by workers' redisStateManager
+// proxies off requestKey, dispatches each to state (the producer's real
+// StateManager), and posts the stateResponse back on responseKey. The block
+// producer runs this in a loop alongside PollReplies so workers' state reads
+// and writes actually reach the state they're proxying.
+func (d *RedisDispatcher) ServeStateRequests(ctx context.Context, state runtime.StateManager, lastID string) (string, error) {
+    streams, err := d.client.XRead(ctx, &redis.XReadArgs{
+        Streams: []string{d.requestKey, lastID},
+        Block:   time.Second,
+        Count:   64,
+    }).Result()
+    if err == redis.Nil {
+        return lastID, nil
+    }
+    if err != nil {
+        return lastID, fmt.Errorf("pubsub: failed to read state requests: %w", err)
+    }
+
+    for _, stream := range streams {
+        for _, msg := range stream.Messages {
+            lastID = msg.ID
+            payload, ok := msg.Values["payload"].(string)
+            if !ok {
+                continue
+            }
+
+            var req stateRequest
+            if err := runtime.Deserialize([]byte(payload), &req); err != nil {
+                continue
+            }
+
+            resp := handleStateRequest(ctx, state, req)
+            respBytes, err := runtime.Serialize(resp)
+            if err != nil {
+                continue
+            }
+            if err := d.client.XAdd(ctx, &redis.XAddArgs{
+                Stream: d.responseKey,
+                MaxLen: stateStreamMaxLen,
+                Approx: true,
+                Values: map[string]any{"payload": respBytes},
+            }).Err(); err != nil {
+                return lastID, fmt.Errorf("pubsub: failed to post state response: %w", err)
+            }
+        }
+    }
+
+    return lastID, nil
+}
+
+// ReclaimDeadWork reassigns pending entries idle for longer than minIdle
+// (a crashed consumer never acked them) to consumer via XAUTOCLAIM.
+func (d *RedisDispatcher) ReclaimDeadWork(ctx context.Context, consumer string, minIdle time.Duration) error {
+    _, _, err := d.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+        Stream:   d.workStream,
+        Group:    d.group,
+        Consumer: consumer,
+        MinIdle:  minIdle,
+        Start:    "0-0",
+        Count:    64,
+    }).Result()
+    if err != nil {
+        return fmt.Errorf("pubsub: failed to reclaim dead work: %w", err)
+    }
+    return nil
+}
+
+func newRequestID() string {
+    var b [16]byte
+    _, _ = rand.Read(b[:])
+    return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,480 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/ava-labs/avalanchego/database"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/x/contracts/runtime"
+)
+
+// manifestNotFoundErr is the stateResponse.Err sentinel dispatchStateRequest
+// returns for "GetManifest" when the producer has no manifest stored for
+// the contract. redisStateManager.GetManifest matches it back to
+// database.ErrNotFound instead of wrapping it as an opaque error string.
+const manifestNotFoundErr = "pubsub: no manifest stored for contract"
+
+// stateStreamMaxLen approximately bounds the request/response streams a
+// redisStateManager round-trips through: without a cap, a long-lived
+// producer process accumulates every state RPC ever made for the life of
+// the process. Approximate trimming (~) lets Redis drop whole radix-tree
+// nodes instead of paying for an exact trim on every XAdd.
+const stateStreamMaxLen = 10_000
+
+// stateRequest/stateResponse are the wire records exchanged between a worker
+// and the producer over the state RPC streams below. Every
+// runtime.StateManager method, plus runtime.ManifestStore's, is shimmed; a
+// worker never holds the producer's real state, only a proxy to it.
+type stateRequest struct {
+    ID     string
+    Method string
+    Args   []byte
+}
+
+type stateResponse struct {
+    ID     string
+    Result []byte
+    Err    string
+}
+
+// redisStateManager implements runtime.StateManager by round-tripping every
+// call over a pair of Redis streams back to the producer process that owns
+// the real state. It lets a worker execute a contract call without ever
+// holding block state locally.
+type redisStateManager struct {
+    client      *redis.Client
+    requestKey  string
+    responseKey string
+
+    // lastIDMu guards lastID: awaitResponse only ever runs one call at a
+    // time in practice, but a mutex here costs nothing and rules out a
+    // torn read/write if that ever changes.
+    lastIDMu sync.Mutex
+    lastID   string
+}
+
+func newRedisStateManager(client *redis.Client, requestKey, responseKey string) *redisStateManager {
+    return &redisStateManager{
+        client:      client,
+        requestKey:  requestKey,
+        responseKey: responseKey,
+        lastID:      "0",
+    }
+}
+
+func (s *redisStateManager) call(ctx context.Context, method string, args any) ([]byte, error) {
+    resp, err := s.roundTrip(ctx, method, args)
+    if err != nil {
+        return nil, err
+    }
+    if resp.Err != "" {
+        return nil, fmt.Errorf("pubsub: producer returned error for %s: %s", method, resp.Err)
+    }
+    return resp.Result, nil
+}
+
+// roundTrip is the request/response plumbing call builds on. GetManifest
+// uses it directly, rather than call, so it can translate the producer's
+// "no manifest stored" response into database.ErrNotFound instead of an
+// opaque wrapped string: errors.Is(err, database.ErrNotFound) is the
+// implicit not-found contract every runtime.ManifestStore implementation
+// is expected to honor, and lookupManifest (manifest.go) relies on it.
+func (s *redisStateManager) roundTrip(ctx context.Context, method string, args any) (*stateResponse, error) {
+    argBytes, err := runtime.Serialize(args)
+    if err != nil {
+        return nil, fmt.Errorf("pubsub: failed to serialize %s args: %w", method, err)
+    }
+
+    req := stateRequest{
+        ID:     newRequestID(),
+        Method: method,
+        Args:   argBytes,
+    }
+    reqBytes, err := runtime.Serialize(req)
+    if err != nil {
+        return nil, fmt.Errorf("pubsub: failed to serialize state request: %w", err)
+    }
+
+    if err := s.client.XAdd(ctx, &redis.XAddArgs{
+        Stream: s.requestKey,
+        MaxLen: stateStreamMaxLen,
+        Approx: true,
+        Values: map[string]any{"payload": reqBytes},
+    }).Err(); err != nil {
+        return nil, fmt.Errorf("pubsub: failed to submit state request: %w", err)
+    }
+
+    return s.awaitResponse(ctx, req.ID)
+}
+
+// awaitResponse polls the response stream for a reply matching requestID.
+// The producer is expected to reply promptly since it is only shimming a
+// local state read/write, not doing meaningful I/O of its own.
+//
+// It resumes from s.lastID rather than rescanning the stream from "0" on
+// every call: a call that makes N state requests during its lifetime would
+// otherwise re-read the full historical response stream N times.
+func (s *redisStateManager) awaitResponse(ctx context.Context, requestID string) (*stateResponse, error) {
+    s.lastIDMu.Lock()
+    lastID := s.lastID
+    s.lastIDMu.Unlock()
+
+    for {
+        streams, err := s.client.XRead(ctx, &redis.XReadArgs{
+            Streams: []string{s.responseKey, lastID},
+            Block:   0,
+            Count:   32,
+        }).Result()
+        if err != nil {
+            return nil, fmt.Errorf("pubsub: failed to read state responses: %w", err)
+        }
+
+        for _, stream := range streams {
+            for _, msg := range stream.Messages {
+                lastID = msg.ID
+                s.lastIDMu.Lock()
+                s.lastID = lastID
+                s.lastIDMu.Unlock()
+
+                payload, ok := msg.Values["payload"].(string)
+                if !ok {
+                    continue
+                }
+
+                var resp stateResponse
+                if err := runtime.Deserialize([]byte(payload), &resp); err != nil {
+                    continue
+                }
+                if resp.ID == requestID {
+                    return &resp, nil
+                }
+            }
+        }
+    }
+}
+
+func (s *redisStateManager) GetBalance(ctx context.Context, address codec.Address) (uint64, error) {
+    result, err := s.call(ctx, "GetBalance", address)
+    if err != nil {
+        return 0, err
+    }
+    var balance uint64
+    if err := runtime.Deserialize(result, &balance); err != nil {
+        return 0, err
+    }
+    return balance, nil
+}
+
+func (s *redisStateManager) TransferBalance(ctx context.Context, from, to codec.Address, amount uint64) error {
+    _, err := s.call(ctx, "TransferBalance", struct {
+        From, To codec.Address
+        Amount   uint64
+    }{from, to, amount})
+    return err
+}
+
+func (s *redisStateManager) GetContractState(address codec.Address) state.Mutable {
+    return &redisContractState{manager: s, address: address}
+}
+
+// redisContractState implements state.Mutable by round-tripping Get/Insert/
+// Remove over the same state RPC streams redisStateManager's other methods
+// use, scoped to a single contract address. It is what lets state.put and
+// state.delete (state_access.go) work unmodified on a worker: they call
+// callInfo.State.GetContractState(addr) without knowing it is a proxy.
+// redisStateManager does not implement runtime.RangeIterable, so
+// iterator_create_storage_prefix (iterator.go) traps on a worker.
+type redisContractState struct {
+    manager *redisStateManager
+    address codec.Address
+}
+
+func (s *redisContractState) GetValue(ctx context.Context, key []byte) ([]byte, error) {
+    return s.manager.call(ctx, "ContractStateGetValue", struct {
+        Address codec.Address
+        Key     []byte
+    }{s.address, key})
+}
+
+func (s *redisContractState) Insert(ctx context.Context, key, value []byte) error {
+    _, err := s.manager.call(ctx, "ContractStateInsert", struct {
+        Address codec.Address
+        Key     []byte
+        Value   []byte
+    }{s.address, key, value})
+    return err
+}
+
+func (s *redisContractState) Remove(ctx context.Context, key []byte) error {
+    _, err := s.manager.call(ctx, "ContractStateRemove", struct {
+        Address codec.Address
+        Key     []byte
+    }{s.address, key})
+    return err
+}
+
+func (s *redisStateManager) GetAccountContract(ctx context.Context, account codec.Address) (runtime.ContractID, error) {
+    result, err := s.call(ctx, "GetAccountContract", account)
+    if err != nil {
+        return nil, err
+    }
+    var id runtime.ContractID
+    if err := runtime.Deserialize(result, &id); err != nil {
+        return nil, err
+    }
+    return id, nil
+}
+
+func (s *redisStateManager) GetContractBytes(ctx context.Context, contractID runtime.ContractID) ([]byte, error) {
+    return s.call(ctx, "GetContractBytes", contractID)
+}
+
+func (s *redisStateManager) NewAccountWithContract(ctx context.Context, contractID runtime.ContractID, accountCreationData []byte) (codec.Address, error) {
+    result, err := s.call(ctx, "NewAccountWithContract", struct {
+        ContractID           runtime.ContractID
+        AccountCreationData  []byte
+    }{contractID, accountCreationData})
+    if err != nil {
+        return codec.Address{}, err
+    }
+    var addr codec.Address
+    if err := runtime.Deserialize(result, &addr); err != nil {
+        return codec.Address{}, err
+    }
+    return addr, nil
+}
+
+func (s *redisStateManager) SetAccountContract(ctx context.Context, account codec.Address, contractID runtime.ContractID) error {
+    _, err := s.call(ctx, "SetAccountContract", struct {
+        Account    codec.Address
+        ContractID runtime.ContractID
+    }{account, contractID})
+    return err
+}
+
+func (s *redisStateManager) SetContractBytes(ctx context.Context, contractID runtime.ContractID, contractBytes []byte) error {
+    _, err := s.call(ctx, "SetContractBytes", struct {
+        ContractID     runtime.ContractID
+        ContractBytes  []byte
+    }{contractID, contractBytes})
+    return err
+}
+
+// GetManifest and SetManifest satisfy runtime.ManifestStore by round-
+// tripping over the same state RPC streams as every other method on
+// redisStateManager, so a worker's manifest-gated checks (CallContract's
+// HasFunction/RequiredFlags/AllowedCallees enforcement) see the same
+// manifests the producer would.
+func (s *redisStateManager) GetManifest(ctx context.Context, contractID runtime.ContractID) (*runtime.Manifest, error) {
+    resp, err := s.roundTrip(ctx, "GetManifest", contractID)
+    if err != nil {
+        return nil, err
+    }
+    if resp.Err == manifestNotFoundErr {
+        return nil, database.ErrNotFound
+    }
+    if resp.Err != "" {
+        return nil, fmt.Errorf("pubsub: producer returned error for GetManifest: %s", resp.Err)
+    }
+    var manifest runtime.Manifest
+    if err := runtime.Deserialize(resp.Result, &manifest); err != nil {
+        return nil, err
+    }
+    return &manifest, nil
+}
+
+func (s *redisStateManager) SetManifest(ctx context.Context, contractID runtime.ContractID, manifest runtime.Manifest) error {
+    _, err := s.call(ctx, "SetManifest", struct {
+        ContractID runtime.ContractID
+        Manifest   runtime.Manifest
+    }{contractID, manifest})
+    return err
+}
+
+// handleStateRequest runs on the producer, which holds the real state: it
+// dispatches req to the matching runtime.StateManager method and returns the
+// stateResponse to post back on the response stream. It is the other half of
+// redisStateManager.call, so its Method names and argument/result encodings
+// must match that type's methods exactly.
+func handleStateRequest(ctx context.Context, state runtime.StateManager, req stateRequest) stateResponse {
+    result, err := dispatchStateRequest(ctx, state, req)
+    if err != nil {
+        return stateResponse{ID: req.ID, Err: err.Error()}
+    }
+    return stateResponse{ID: req.ID, Result: result}
+}
+
+func dispatchStateRequest(ctx context.Context, state runtime.StateManager, req stateRequest) ([]byte, error) {
+    switch req.Method {
+    case "GetBalance":
+        var address codec.Address
+        if err := runtime.Deserialize(req.Args, &address); err != nil {
+            return nil, err
+        }
+        balance, err := state.GetBalance(ctx, address)
+        if err != nil {
+            return nil, err
+        }
+        return runtime.Serialize(balance)
+
+    case "TransferBalance":
+        var args struct {
+            From, To codec.Address
+            Amount   uint64
+        }
+        if err := runtime.Deserialize(req.Args, &args); err != nil {
+            return nil, err
+        }
+        if err := state.TransferBalance(ctx, args.From, args.To, args.Amount); err != nil {
+            return nil, err
+        }
+        return nil, nil
+
+    case "GetAccountContract":
+        var account codec.Address
+        if err := runtime.Deserialize(req.Args, &account); err != nil {
+            return nil, err
+        }
+        id, err := state.GetAccountContract(ctx, account)
+        if err != nil {
+            return nil, err
+        }
+        return runtime.Serialize(id)
+
+    case "GetContractBytes":
+        var contractID runtime.ContractID
+        if err := runtime.Deserialize(req.Args, &contractID); err != nil {
+            return nil, err
+        }
+        // The client returns this Result directly with no Deserialize step,
+        // so it must be the raw contract bytes, not a Serialize-wrapped value.
+        return state.GetContractBytes(ctx, contractID)
+
+    case "NewAccountWithContract":
+        var args struct {
+            ContractID          runtime.ContractID
+            AccountCreationData []byte
+        }
+        if err := runtime.Deserialize(req.Args, &args); err != nil {
+            return nil, err
+        }
+        addr, err := state.NewAccountWithContract(ctx, args.ContractID, args.AccountCreationData)
+        if err != nil {
+            return nil, err
+        }
+        return runtime.Serialize(addr)
+
+    case "SetAccountContract":
+        var args struct {
+            Account    codec.Address
+            ContractID runtime.ContractID
+        }
+        if err := runtime.Deserialize(req.Args, &args); err != nil {
+            return nil, err
+        }
+        if err := state.SetAccountContract(ctx, args.Account, args.ContractID); err != nil {
+            return nil, err
+        }
+        return nil, nil
+
+    case "SetContractBytes":
+        var args struct {
+            ContractID    runtime.ContractID
+            ContractBytes []byte
+        }
+        if err := runtime.Deserialize(req.Args, &args); err != nil {
+            return nil, err
+        }
+        if err := state.SetContractBytes(ctx, args.ContractID, args.ContractBytes); err != nil {
+            return nil, err
+        }
+        return nil, nil
+
+    case "ContractStateGetValue":
+        var args struct {
+            Address codec.Address
+            Key     []byte
+        }
+        if err := runtime.Deserialize(req.Args, &args); err != nil {
+            return nil, err
+        }
+        // The client returns this Result directly with no Deserialize step,
+        // matching GetContractBytes above: it is the raw stored value.
+        return state.GetContractState(args.Address).GetValue(ctx, args.Key)
+
+    case "ContractStateInsert":
+        var args struct {
+            Address codec.Address
+            Key     []byte
+            Value   []byte
+        }
+        if err := runtime.Deserialize(req.Args, &args); err != nil {
+            return nil, err
+        }
+        if err := state.GetContractState(args.Address).Insert(ctx, args.Key, args.Value); err != nil {
+            return nil, err
+        }
+        return nil, nil
+
+    case "ContractStateRemove":
+        var args struct {
+            Address codec.Address
+            Key     []byte
+        }
+        if err := runtime.Deserialize(req.Args, &args); err != nil {
+            return nil, err
+        }
+        if err := state.GetContractState(args.Address).Remove(ctx, args.Key); err != nil {
+            return nil, err
+        }
+        return nil, nil
+
+    case "GetManifest":
+        var contractID runtime.ContractID
+        if err := runtime.Deserialize(req.Args, &contractID); err != nil {
+            return nil, err
+        }
+        store, ok := state.(runtime.ManifestStore)
+        if !ok {
+            return nil, fmt.Errorf("pubsub: producer's StateManager does not implement runtime.ManifestStore")
+        }
+        manifest, err := store.GetManifest(ctx, contractID)
+        if errors.Is(err, database.ErrNotFound) {
+            return nil, errors.New(manifestNotFoundErr)
+        }
+        if err != nil {
+            return nil, err
+        }
+        return runtime.Serialize(*manifest)
+
+    case "SetManifest":
+        var args struct {
+            ContractID runtime.ContractID
+            Manifest   runtime.Manifest
+        }
+        if err := runtime.Deserialize(req.Args, &args); err != nil {
+            return nil, err
+        }
+        store, ok := state.(runtime.ManifestStore)
+        if !ok {
+            return nil, fmt.Errorf("pubsub: producer's StateManager does not implement runtime.ManifestStore")
+        }
+        if err := store.SetManifest(ctx, args.ContractID, args.Manifest); err != nil {
+            return nil, err
+        }
+        return nil, nil
+
+    default:
+        return nil, fmt.Errorf("pubsub: producer received unknown state method %q", req.Method)
+    }
+}
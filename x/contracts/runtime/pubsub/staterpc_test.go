@@ -0,0 +1,291 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package pubsub
+
+import (
+    "context"
+    "errors"
+    "testing"
+
+    "github.com/ava-labs/avalanchego/database"
+    "github.com/stretchr/testify/require"
+
+    "github.com/ava-labs/hypersdk/codec"
+    "github.com/ava-labs/hypersdk/state"
+    "github.com/ava-labs/hypersdk/x/contracts/runtime"
+)
+
+// fakeStateManager is a minimal runtime.StateManager double that keeps its
+// state in memory, so dispatchStateRequest's decoding can be
+// checked without a live Redis connection. It also implements
+// runtime.ManifestStore so dispatchStateRequest's GetManifest/SetManifest
+// cases can be checked the same way.
+type fakeStateManager struct {
+    balances      map[codec.Address]uint64
+    contracts     map[codec.Address]runtime.ContractID
+    bytecode      map[string][]byte
+    contractState map[codec.Address]*fakeMutableState
+    manifests     map[string]*runtime.Manifest
+}
+
+func newFakeStateManager() *fakeStateManager {
+    return &fakeStateManager{
+        balances:      map[codec.Address]uint64{},
+        contracts:     map[codec.Address]runtime.ContractID{},
+        bytecode:      map[string][]byte{},
+        contractState: map[codec.Address]*fakeMutableState{},
+        manifests:     map[string]*runtime.Manifest{},
+    }
+}
+
+// fakeMutableState is a minimal state.Mutable double, keyed by address in
+// fakeStateManager.contractState, so dispatchStateRequest's ContractState*
+// cases can be checked without a live Redis connection or real StateManager.
+type fakeMutableState struct {
+    kv map[string][]byte
+}
+
+func (m *fakeMutableState) GetValue(_ context.Context, key []byte) ([]byte, error) {
+    value, ok := m.kv[string(key)]
+    if !ok {
+        return nil, errors.New("fakeMutableState: no value for key")
+    }
+    return value, nil
+}
+
+func (m *fakeMutableState) Insert(_ context.Context, key, value []byte) error {
+    m.kv[string(key)] = value
+    return nil
+}
+
+func (m *fakeMutableState) Remove(_ context.Context, key []byte) error {
+    delete(m.kv, string(key))
+    return nil
+}
+
+func (f *fakeStateManager) GetBalance(_ context.Context, address codec.Address) (uint64, error) {
+    return f.balances[address], nil
+}
+
+func (f *fakeStateManager) TransferBalance(_ context.Context, from, to codec.Address, amount uint64) error {
+    if f.balances[from] < amount {
+        return errors.New("fakeStateManager: insufficient balance")
+    }
+    f.balances[from] -= amount
+    f.balances[to] += amount
+    return nil
+}
+
+func (f *fakeStateManager) GetContractState(address codec.Address) state.Mutable {
+    m, ok := f.contractState[address]
+    if !ok {
+        m = &fakeMutableState{kv: map[string][]byte{}}
+        f.contractState[address] = m
+    }
+    return m
+}
+
+func (f *fakeStateManager) GetAccountContract(_ context.Context, account codec.Address) (runtime.ContractID, error) {
+    id, ok := f.contracts[account]
+    if !ok {
+        return nil, errors.New("fakeStateManager: no contract for account")
+    }
+    return id, nil
+}
+
+func (f *fakeStateManager) GetContractBytes(_ context.Context, contractID runtime.ContractID) ([]byte, error) {
+    bytecode, ok := f.bytecode[string(contractID)]
+    if !ok {
+        return nil, errors.New("fakeStateManager: no bytecode for contract")
+    }
+    return bytecode, nil
+}
+
+func (f *fakeStateManager) NewAccountWithContract(context.Context, runtime.ContractID, []byte) (codec.Address, error) {
+    return codec.Address{}, errors.New("fakeStateManager: not implemented")
+}
+
+func (f *fakeStateManager) SetAccountContract(_ context.Context, account codec.Address, contractID runtime.ContractID) error {
+    f.contracts[account] = contractID
+    return nil
+}
+
+func (f *fakeStateManager) SetContractBytes(_ context.Context, contractID runtime.ContractID, contractBytes []byte) error {
+    f.bytecode[string(contractID)] = contractBytes
+    return nil
+}
+
+func (f *fakeStateManager) GetManifest(_ context.Context, contractID runtime.ContractID) (*runtime.Manifest, error) {
+    manifest, ok := f.manifests[string(contractID)]
+    if !ok {
+        return nil, database.ErrNotFound
+    }
+    return manifest, nil
+}
+
+func (f *fakeStateManager) SetManifest(_ context.Context, contractID runtime.ContractID, manifest runtime.Manifest) error {
+    f.manifests[string(contractID)] = &manifest
+    return nil
+}
+
+// TestDispatchStateRequestRoundTrips guards the producer side of the state
+// RPC shim: every method a worker's redisStateManager can call must decode
+// its args and re-encode its result the same way the client expects, or a
+// worker calling e.g. GetAccountContract will hang or get garbage back.
+func TestDispatchStateRequestRoundTrips(t *testing.T) {
+    require := require.New(t)
+
+    account := codec.CreateAddress(0, [32]byte{1})
+    contractID := runtime.ContractID([]byte("contract-1"))
+    fake := newFakeStateManager()
+    fake.balances[account] = 100
+    fake.contracts[account] = contractID
+    fake.bytecode[string(contractID)] = []byte("wasm-bytes")
+
+    t.Run("GetBalance", func(t *testing.T) {
+        args, err := runtime.Serialize(account)
+        require.NoError(err)
+
+        result, err := dispatchStateRequest(context.Background(), fake, stateRequest{Method: "GetBalance", Args: args})
+        require.NoError(err)
+
+        var balance uint64
+        require.NoError(runtime.Deserialize(result, &balance))
+        require.Equal(uint64(100), balance)
+    })
+
+    t.Run("GetAccountContract", func(t *testing.T) {
+        args, err := runtime.Serialize(account)
+        require.NoError(err)
+
+        result, err := dispatchStateRequest(context.Background(), fake, stateRequest{Method: "GetAccountContract", Args: args})
+        require.NoError(err)
+
+        var id runtime.ContractID
+        require.NoError(runtime.Deserialize(result, &id))
+        require.Equal(contractID, id)
+    })
+
+    t.Run("GetContractBytes returns raw bytes, not Serialize-wrapped", func(t *testing.T) {
+        args, err := runtime.Serialize(contractID)
+        require.NoError(err)
+
+        result, err := dispatchStateRequest(context.Background(), fake, stateRequest{Method: "GetContractBytes", Args: args})
+        require.NoError(err)
+        require.Equal([]byte("wasm-bytes"), result)
+    })
+
+    t.Run("TransferBalance", func(t *testing.T) {
+        other := codec.CreateAddress(0, [32]byte{2})
+        args, err := runtime.Serialize(struct {
+            From, To codec.Address
+            Amount   uint64
+        }{account, other, 40})
+        require.NoError(err)
+
+        _, err = dispatchStateRequest(context.Background(), fake, stateRequest{Method: "TransferBalance", Args: args})
+        require.NoError(err)
+        require.Equal(uint64(60), fake.balances[account])
+        require.Equal(uint64(40), fake.balances[other])
+    })
+
+    t.Run("ContractStateInsert then ContractStateGetValue round-trips", func(t *testing.T) {
+        key, value := []byte("k"), []byte("v")
+        insertArgs, err := runtime.Serialize(struct {
+            Address codec.Address
+            Key     []byte
+            Value   []byte
+        }{account, key, value})
+        require.NoError(err)
+
+        _, err = dispatchStateRequest(context.Background(), fake, stateRequest{Method: "ContractStateInsert", Args: insertArgs})
+        require.NoError(err)
+
+        getArgs, err := runtime.Serialize(struct {
+            Address codec.Address
+            Key     []byte
+        }{account, key})
+        require.NoError(err)
+
+        result, err := dispatchStateRequest(context.Background(), fake, stateRequest{Method: "ContractStateGetValue", Args: getArgs})
+        require.NoError(err)
+        require.Equal(value, result)
+    })
+
+    t.Run("ContractStateRemove deletes a previously inserted key", func(t *testing.T) {
+        key, value := []byte("k2"), []byte("v2")
+        insertArgs, err := runtime.Serialize(struct {
+            Address codec.Address
+            Key     []byte
+            Value   []byte
+        }{account, key, value})
+        require.NoError(err)
+        _, err = dispatchStateRequest(context.Background(), fake, stateRequest{Method: "ContractStateInsert", Args: insertArgs})
+        require.NoError(err)
+
+        removeArgs, err := runtime.Serialize(struct {
+            Address codec.Address
+            Key     []byte
+        }{account, key})
+        require.NoError(err)
+        _, err = dispatchStateRequest(context.Background(), fake, stateRequest{Method: "ContractStateRemove", Args: removeArgs})
+        require.NoError(err)
+
+        _, err = dispatchStateRequest(context.Background(), fake, stateRequest{Method: "ContractStateGetValue", Args: removeArgs})
+        require.Error(err)
+    })
+
+    t.Run("SetManifest then GetManifest round-trips", func(t *testing.T) {
+        manifest := runtime.Manifest{
+            Functions:     []runtime.ManifestFunction{{Name: "get_value"}},
+            RequiredFlags: runtime.ReadStates,
+        }
+        setArgs, err := runtime.Serialize(struct {
+            ContractID runtime.ContractID
+            Manifest   runtime.Manifest
+        }{contractID, manifest})
+        require.NoError(err)
+        _, err = dispatchStateRequest(context.Background(), fake, stateRequest{Method: "SetManifest", Args: setArgs})
+        require.NoError(err)
+
+        getArgs, err := runtime.Serialize(contractID)
+        require.NoError(err)
+        result, err := dispatchStateRequest(context.Background(), fake, stateRequest{Method: "GetManifest", Args: getArgs})
+        require.NoError(err)
+
+        var got runtime.Manifest
+        require.NoError(runtime.Deserialize(result, &got))
+        require.Equal(manifest, got)
+    })
+
+    t.Run("GetManifest for an unknown contract returns the not-found sentinel, not a generic error", func(t *testing.T) {
+        args, err := runtime.Serialize(runtime.ContractID([]byte("no-such-contract")))
+        require.NoError(err)
+
+        _, err = dispatchStateRequest(context.Background(), fake, stateRequest{Method: "GetManifest", Args: args})
+        require.EqualError(err, manifestNotFoundErr)
+    })
+
+    t.Run("unknown method", func(t *testing.T) {
+        _, err := dispatchStateRequest(context.Background(), fake, stateRequest{Method: "NotAMethod"})
+        require.Error(err)
+        require.Contains(err.Error(), "NotAMethod")
+    })
+}
+
+// TestHandleStateRequestWrapsErrors confirms a StateManager error is carried
+// back on stateResponse.Err rather than lost, since handleStateRequest has
+// no other way to report failure to the waiting worker.
+func TestHandleStateRequestWrapsErrors(t *testing.T) {
+    require := require.New(t)
+
+    fake := newFakeStateManager()
+    args, err := runtime.Serialize(codec.CreateAddress(0, [32]byte{9}))
+    require.NoError(err)
+
+    resp := handleStateRequest(context.Background(), fake, stateRequest{ID: "req-1", Method: "GetAccountContract", Args: args})
+    require.Equal("req-1", resp.ID)
+    require.Nil(resp.Result)
+    require.Contains(resp.Err, "no contract for account")
+}
@@ -0,0 +1,140 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package pubsub
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/ava-labs/hypersdk/x/contracts/runtime"
+)
+
+// Worker pulls CallInfo records off a Redis work stream, executes them on
+// its own WasmRuntime using a state RPC shim back to the producer, and
+// reports results on the reply stream.
+type Worker struct {
+    client      *redis.Client
+    runtime     *runtime.WasmRuntime
+    workStream  string
+    replyStream string
+    group       string
+    consumer    string
+
+    // state is shared across every call this worker handles: its request/
+    // response keys are derived from workStream alone, not anything
+    // call-specific, so constructing a fresh redisStateManager per call
+    // would reset staterpc.go's response-stream read cursor (lastID) back
+    // to "0" every time, defeating the cursor cache and forcing a full
+    // scan of the shared response stream on each call's first state RPC.
+    state *redisStateManager
+}
+
+func NewWorker(client *redis.Client, rt *runtime.WasmRuntime, workStream, replyStream, group, consumer string) *Worker {
+    w := &Worker{
+        client:      client,
+        runtime:     rt,
+        workStream:  workStream,
+        replyStream: replyStream,
+        group:       group,
+        consumer:    consumer,
+    }
+    w.state = newRedisStateManager(client, w.requestKey(), w.responseKey())
+    return w
+}
+
+// Run reads and executes work until ctx is canceled. A dead worker's
+// in-flight work is detected and reclaimed by ReclaimDeadWork via
+// XAUTOCLAIM's own pending-entry idle time, not by anything Run does here.
+func (w *Worker) Run(ctx context.Context) error {
+    for {
+        select {
+        case <-ctx.Done():
+            return ctx.Err()
+        default:
+        }
+
+        streams, err := w.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+            Group:    w.group,
+            Consumer: w.consumer,
+            Streams:  []string{w.workStream, ">"},
+            Block:    time.Second,
+            Count:    1,
+        }).Result()
+        if err == redis.Nil {
+            continue
+        }
+        if err != nil {
+            return fmt.Errorf("pubsub: failed to read work: %w", err)
+        }
+
+        for _, stream := range streams {
+            for _, msg := range stream.Messages {
+                w.handle(ctx, msg)
+                w.client.XAck(ctx, w.workStream, w.group, msg.ID)
+            }
+        }
+    }
+}
+
+func (w *Worker) handle(ctx context.Context, msg redis.XMessage) {
+    payload, ok := msg.Values["payload"].(string)
+    if !ok {
+        return
+    }
+
+    var record callRecord
+    if err := runtime.Deserialize([]byte(payload), &record); err != nil {
+        // record.ID never got decoded, so there is no pending Submit
+        // channel to reply to: posting under the zero-value ID would just
+        // be dropped by the producer, not delivered anywhere.
+        return
+    }
+
+    call := &runtime.CallInfo{
+        State:        w.state,
+        FunctionName: record.FunctionName,
+        Params:       record.Params,
+        Fuel:         record.Fuel,
+        Height:       record.Height,
+        Timestamp:    record.Timestamp,
+        Value:        record.Value,
+        // Forward the submitter's actual CallFlags: the scheduler marking a
+        // call conflict-free is a scheduling judgment, not a grant of
+        // capabilities, so a worker must run the call with exactly the
+        // flags it would have run with in-process.
+        CallFlags: record.CallFlags,
+    }
+    copy(call.Actor[:], record.Actor)
+    copy(call.Contract[:], record.Contract)
+    copy(call.ActionID[:], record.ActionID)
+
+    res, err := w.runtime.CallContract(ctx, call)
+    if err != nil {
+        w.reply(ctx, record.ID, Result{Err: err})
+        return
+    }
+
+    w.reply(ctx, record.ID, Result{
+        Return: res.Return,
+        Events: res.Events,
+        Stats:  res.Stats,
+    })
+}
+
+func (w *Worker) reply(ctx context.Context, requestID string, result Result) {
+    payload, err := runtime.Serialize(resultRecord{ID: requestID, Result: result})
+    if err != nil {
+        return
+    }
+    w.client.XAdd(ctx, &redis.XAddArgs{
+        Stream: w.replyStream,
+        Values: map[string]any{"payload": payload},
+    })
+}
+
+func (w *Worker) requestKey() string  { return w.workStream + ".state.req" }
+func (w *Worker) responseKey() string { return w.workStream + ".state.resp" }
@@ -5,7 +5,10 @@ package runtime
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"reflect"
+	"sync"
 	"sync/atomic"
 
 	"github.com/ava-labs/avalanchego/cache"
@@ -27,8 +30,20 @@ type WasmRuntime struct {
 
     // New fields for ephemeral support
     blockStats BlockStats
-    validator  ModuleValidator
-    cache      CacheStrategy
+
+    // validators and caches are ordered fallback chains: validators run in
+    // sequence per validationMode, caches are probed in order with
+    // write-through PutModule to every faster tier on a hit.
+    validators     []ModuleValidator
+    validationMode ValidationMode
+    caches         []CacheStrategy
+    tierHits       []uint64
+
+    // eventSubs backs WatchLogs; protected by eventSubsMu.
+    eventSubsMu sync.Mutex
+    eventSubs   []*eventSubscription
+
+    pinningState
 }
 
 type StateManager interface {
@@ -75,14 +90,25 @@ func NewRuntime(
             }
             return len(id) + len(bytes)
         }),
-        validator: cfg.Validator,
-        cache:     cfg.Cache,
+        pinningState: pinningState{
+            pinnedModules: map[string]*wasmtime.Module{},
+        },
+    }
+
+    if cfg.Validator != nil {
+        runtime.validators = append(runtime.validators, cfg.Validator)
+    }
+    if cfg.Cache != nil {
+        runtime.caches = append(runtime.caches, cfg.Cache)
+        runtime.tierHits = append(runtime.tierHits, 0)
     }
 
     hostImports.AddModule(NewLogModule())
     hostImports.AddModule(NewBalanceModule())
     hostImports.AddModule(NewStateAccessModule())
     hostImports.AddModule(NewContractModule(runtime))
+    hostImports.AddModule(NewEventsModule())
+    hostImports.AddModule(NewIteratorModule())
 
     linker, err := hostImports.createLinker(runtime)
     if err != nil {
@@ -99,14 +125,34 @@ func (r *WasmRuntime) WithDefaults(callInfo CallInfo) CallContext {
 }
 
 func (r *WasmRuntime) getModule(ctx context.Context, callInfo *CallInfo, id []byte) (*wasmtime.Module, error) {
-    // Try custom cache strategy first
-    if r.cache != nil {
-        if mod, ok := r.cache.GetModule(string(id)); ok {
-            atomic.AddUint64(&r.blockStats.CacheHits, 1)
+    // Pinned modules bypass the LRU entirely: hot system contracts never
+    // pay compile cost or get evicted.
+    r.pinnedMu.Lock()
+    mod, ok := r.pinnedModules[string(id)]
+    r.pinnedMu.Unlock()
+    if ok {
+        atomic.AddUint64(&r.blockStats.PinnedHits, 1)
+        return mod, nil
+    }
+
+    if r.cfg.PinnedDir != "" {
+        if mod, ok := r.pinnedStore().Get(id); ok {
+            atomic.AddUint64(&r.blockStats.PinnedHits, 1)
+
+            r.pinnedMu.Lock()
+            r.pinnedModules[string(id)] = mod
+            r.pinnedMu.Unlock()
+
             return mod, nil
         }
     }
 
+    // Try the custom cache chain, fastest tier first
+    if mod, _, ok := r.getFromCacheChain(string(id)); ok {
+        atomic.AddUint64(&r.blockStats.CacheHits, 1)
+        return mod, nil
+    }
+
     // Try default cache
     if mod, ok := r.contractCache.Get(string(id)); ok {
         atomic.AddUint64(&r.blockStats.CacheHits, 1)
@@ -118,11 +164,9 @@ func (r *WasmRuntime) getModule(ctx context.Context, callInfo *CallInfo, id []by
         return nil, err
     }
 
-    // Validate if configured
-    if r.validator != nil {
-        if err := r.validator.ValidateModule(ctx, contractBytes); err != nil {
-            return nil, err
-        }
+    // Validate if any validators are configured
+    if err := r.runValidators(ctx, callInfo.State, id, contractBytes); err != nil {
+        return nil, err
     }
 
     mod, err := wasmtime.NewModule(r.engine, contractBytes)
@@ -130,21 +174,79 @@ func (r *WasmRuntime) getModule(ctx context.Context, callInfo *CallInfo, id []by
         return nil, err
     }
 
-    // Cache the module
-    if r.cache != nil {
-        r.cache.PutModule(string(id), mod)
-    }
+    // Cache the module in every configured tier
+    r.putToCacheChain(string(id), mod)
     r.contractCache.Put(string(id), mod)
 
     return mod, nil
 }
 
-func (r *WasmRuntime) CallContract(ctx context.Context, callInfo *CallInfo) ([]byte, error) {
+func (r *WasmRuntime) CallContract(ctx context.Context, callInfo *CallInfo) (*ExecutionResult, error) {
+    effectiveFlags := callInfo.effectiveFlags()
+    parentCallInfo, nested := callInfoFromContext(ctx)
+    if parentFlags, ok := callFlagsFromContext(ctx); ok {
+        if !parentFlags.Has(AllowCall) {
+            return nil, errors.New("runtime: call trapped: caller frame lacks AllowCall")
+        }
+        // A ReadOnly outer call can never be escalated by a nested call:
+        // the callee's effective flags are the AND of the caller's flags
+        // and whatever the call site itself declared.
+        effectiveFlags &= parentFlags
+    }
+    if nested {
+        if parentContractID, err := parentCallInfo.State.GetAccountContract(ctx, parentCallInfo.Contract); err == nil {
+            parentManifest, err := lookupManifest(ctx, parentCallInfo.State, parentContractID)
+            if err != nil {
+                return nil, err
+            }
+            if parentManifest != nil {
+                if !parentManifest.allowsCallee(callInfo.Contract) {
+                    return nil, fmt.Errorf("runtime: call trapped: caller's manifest does not allow calling %v", callInfo.Contract)
+                }
+            }
+        }
+    }
+    callInfo.CallFlags = effectiveFlags
+    ctx = contextWithCallFlags(ctx, effectiveFlags)
+    ctx = contextWithCallInfo(ctx, callInfo)
+
+    // PreCall hooks run in registration order before the called contract's
+    // state lookup and module instantiation, so a veto never pays for work
+    // the call will not get credit for.
+    for _, hook := range r.cfg.LifecycleHooks {
+        fuelBefore := callInfo.Fuel
+        err := hook.PreCall(ctx, callInfo)
+        r.chargeHookFuel(fuelBefore, callInfo.Fuel)
+        if err != nil {
+            return nil, fmt.Errorf("runtime: call vetoed by lifecycle hook: %w", err)
+        }
+    }
+
+    // A PreCall hook may have mutated callInfo.CallFlags, so re-derive
+    // effectiveFlags and ctx's copy from the live field rather than the
+    // pre-hook local: otherwise the manifest check below and whatever a
+    // nested call inherits both see the stale, pre-hook value.
+    effectiveFlags = callInfo.CallFlags
+    ctx = contextWithCallFlags(ctx, effectiveFlags)
+
     contractID, err := callInfo.State.GetAccountContract(ctx, callInfo.Contract)
     if err != nil {
         return nil, err
     }
 
+    manifest, err := lookupManifest(ctx, callInfo.State, contractID)
+    if err != nil {
+        return nil, err
+    }
+    if manifest != nil {
+        if !manifest.HasFunction(callInfo.FunctionName) {
+            return nil, fmt.Errorf("runtime: function %q is not declared in the contract's manifest", callInfo.FunctionName)
+        }
+        if !effectiveFlags.Has(manifest.RequiredFlags) {
+            return nil, fmt.Errorf("runtime: call lacks the flags the contract's manifest requires")
+        }
+    }
+
     contractModule, err := r.getModule(ctx, callInfo, contractID)
     if err != nil {
         return nil, err
@@ -165,9 +267,47 @@ func (r *WasmRuntime) CallContract(ctx context.Context, callInfo *CallInfo) ([]b
     // Set up call info
     r.setCallInfo(instance.store, callInfo)
     defer r.deleteCallInfo(instance.store)
+    // A guest that forgets to call iterator_release must not leak the
+    // underlying state cursor once this call's instance is disposed.
+    defer callInfo.releaseAllIterators()
 
     // Execute and update stats
-    return instance.Call(ctx, callInfo)
+    result, callErr := instance.Call(ctx, callInfo)
+
+    // PostCall hooks run in registration order and see the call's outcome
+    // even on failure, so they can attribute fees/bookkeeping regardless of
+    // whether the call reverted. A hook's error replaces callErr, letting a
+    // hook fail an otherwise-successful call.
+    for _, hook := range r.cfg.LifecycleHooks {
+        fuelBefore := callInfo.Fuel
+        if hookErr := hook.PostCall(ctx, callInfo, result, callErr); hookErr != nil {
+            callErr = hookErr
+        }
+        r.chargeHookFuel(fuelBefore, callInfo.Fuel)
+    }
+
+    if callErr != nil {
+        return nil, callErr
+    }
+
+    if nested {
+        // Fold this call's notifications into the parent's buffer so the
+        // outermost call publishes and counts them exactly once, in
+        // execution order. A reverted nested call never reaches here, so
+        // its notifications are naturally discarded rather than folded.
+        foldNestedEvents(parentCallInfo, result.Events)
+    } else {
+        r.publishEvents(result.Events)
+
+        var notificationBytes uint64
+        for _, ev := range result.Events {
+            notificationBytes += uint64(len(ev.Data))
+        }
+        atomic.AddUint64(&r.blockStats.NotificationsEmitted, uint64(len(result.Events)))
+        atomic.AddUint64(&r.blockStats.NotificationBytes, notificationBytes)
+    }
+
+    return result, nil
 }
 
 func toMapKey(storeLike wasmtime.Storelike) uintptr {
@@ -190,16 +330,56 @@ func (r *WasmRuntime) deleteCallInfo(storeLike wasmtime.Storelike) {
 
 func (r *WasmRuntime) GetBlockStats() BlockStats {
     return BlockStats{
-        TotalFuelUsed:    atomic.LoadUint64(&r.blockStats.TotalFuelUsed),
-        ContractCalls:    atomic.LoadUint64(&r.blockStats.ContractCalls),
-        AvgExecutionTime: atomic.LoadUint64(&r.blockStats.AvgExecutionTime),
-        CacheHits:        atomic.LoadUint64(&r.blockStats.CacheHits),
+        TotalFuelUsed:        atomic.LoadUint64(&r.blockStats.TotalFuelUsed),
+        ContractCalls:        atomic.LoadUint64(&r.blockStats.ContractCalls),
+        AvgExecutionTime:     atomic.LoadUint64(&r.blockStats.AvgExecutionTime),
+        CacheHits:            atomic.LoadUint64(&r.blockStats.CacheHits),
+        PinnedHits:           atomic.LoadUint64(&r.blockStats.PinnedHits),
+        NotificationsEmitted: atomic.LoadUint64(&r.blockStats.NotificationsEmitted),
+        NotificationBytes:    atomic.LoadUint64(&r.blockStats.NotificationBytes),
+        IteratorNextCalls:    atomic.LoadUint64(&r.blockStats.IteratorNextCalls),
+        IteratorBytesRead:    atomic.LoadUint64(&r.blockStats.IteratorBytesRead),
+        HookFuelUsed:         atomic.LoadUint64(&r.blockStats.HookFuelUsed),
+    }
+}
+
+// chargeHookFuel attributes the fuel a lifecycle hook consumed (by lowering
+// callInfo.Fuel) to HookFuelUsed, separately from the fuel the contract
+// itself burns, so per-contract accounting stays clean. A hook that raises
+// Fuel instead of lowering it is not charged.
+func (r *WasmRuntime) chargeHookFuel(before, after uint64) {
+    if before > after {
+        atomic.AddUint64(&r.blockStats.HookFuelUsed, before-after)
     }
 }
 
-func (r *WasmRuntime) ResetBlockStats() {
+// ResetBlockStats marks a block boundary: every registered hook's
+// PostPersist runs for the block that just finished (finished), stats are
+// zeroed, and then PrePersist runs for the block about to start (next).
+// Hooks run in registration order in both passes.
+func (r *WasmRuntime) ResetBlockStats(ctx context.Context, finished, next BlockContext) error {
+    for _, hook := range r.cfg.LifecycleHooks {
+        if err := hook.PostPersist(ctx, finished); err != nil {
+            return fmt.Errorf("runtime: PostPersist hook failed: %w", err)
+        }
+    }
+
     atomic.StoreUint64(&r.blockStats.TotalFuelUsed, 0)
     atomic.StoreUint64(&r.blockStats.ContractCalls, 0)
     atomic.StoreUint64(&r.blockStats.AvgExecutionTime, 0)
     atomic.StoreUint64(&r.blockStats.CacheHits, 0)
+    atomic.StoreUint64(&r.blockStats.PinnedHits, 0)
+    atomic.StoreUint64(&r.blockStats.NotificationsEmitted, 0)
+    atomic.StoreUint64(&r.blockStats.NotificationBytes, 0)
+    atomic.StoreUint64(&r.blockStats.IteratorNextCalls, 0)
+    atomic.StoreUint64(&r.blockStats.IteratorBytesRead, 0)
+    atomic.StoreUint64(&r.blockStats.HookFuelUsed, 0)
+
+    for _, hook := range r.cfg.LifecycleHooks {
+        if err := hook.PrePersist(ctx, next); err != nil {
+            return fmt.Errorf("runtime: PrePersist hook failed: %w", err)
+        }
+    }
+
+    return nil
 }
\ No newline at end of file
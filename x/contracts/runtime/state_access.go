@@ -0,0 +1,90 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package runtime
+
+import (
+    "context"
+    "errors"
+
+    "github.com/bytecodealliance/wasmtime-go/v25"
+)
+
+// requireWriteStates traps a plain storage write (state.put) unless the
+// calling frame was granted WriteStates, the same way iterator.go traps a
+// read without ReadStates and events.go traps a notification without
+// AllowNotify.
+func requireWriteStates(callInfo *CallInfo) error {
+    if callInfo == nil {
+        return errors.New("runtime: state.put called outside a contract call")
+    }
+    if !callInfo.effectiveFlags().Has(WriteStates) {
+        return errors.New("runtime: state.put trapped: caller frame lacks WriteStates")
+    }
+    return nil
+}
+
+// requireStateModifications traps state.delete unless the calling frame
+// was granted WriteStates and AllowStateModifications: deleting a key is a
+// privileged operation beyond a plain write, since it can retire data
+// another manifest-gated contract relies on existing.
+func requireStateModifications(callInfo *CallInfo) error {
+    if callInfo == nil {
+        return errors.New("runtime: state.delete called outside a contract call")
+    }
+    if !callInfo.effectiveFlags().Has(WriteStates | AllowStateModifications) {
+        return errors.New("runtime: state.delete trapped: caller frame lacks WriteStates and AllowStateModifications")
+    }
+    return nil
+}
+
+// StateAccessModule is the host import module giving guest contracts
+// key/value access to their own account's contract state, alongside
+// IteratorModule's prefix scans over the same storage.
+type StateAccessModule struct{}
+
+func NewStateAccessModule() *StateAccessModule {
+    return &StateAccessModule{}
+}
+
+func (*StateAccessModule) Name() string {
+    return "state"
+}
+
+func (m *StateAccessModule) Register(link *wasmtime.Linker, r *WasmRuntime) error {
+    if err := link.FuncWrap(m.Name(), "put",
+        func(caller *wasmtime.Caller, keyPtr, keyLen, valuePtr, valueLen int32) error {
+            callInfo := r.getCallInfo(caller)
+            if err := requireWriteStates(callInfo); err != nil {
+                return err
+            }
+
+            mem := caller.GetExport(MemoryName).Memory()
+            raw := mem.UnsafeData(caller)
+            key := make([]byte, keyLen)
+            copy(key, raw[keyPtr:keyPtr+keyLen])
+            value := make([]byte, valueLen)
+            copy(value, raw[valuePtr:valuePtr+valueLen])
+
+            return callInfo.State.GetContractState(callInfo.Contract).Insert(context.Background(), key, value)
+        },
+    ); err != nil {
+        return err
+    }
+
+    return link.FuncWrap(m.Name(), "delete",
+        func(caller *wasmtime.Caller, keyPtr, keyLen int32) error {
+            callInfo := r.getCallInfo(caller)
+            if err := requireStateModifications(callInfo); err != nil {
+                return err
+            }
+
+            mem := caller.GetExport(MemoryName).Memory()
+            raw := mem.UnsafeData(caller)
+            key := make([]byte, keyLen)
+            copy(key, raw[keyPtr:keyPtr+keyLen])
+
+            return callInfo.State.GetContractState(callInfo.Contract).Remove(context.Background(), key)
+        },
+    )
+}
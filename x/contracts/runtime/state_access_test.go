@@ -0,0 +1,40 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package runtime
+
+import (
+    "testing"
+
+    "github.com/stretchr/testify/require"
+)
+
+func TestRequireWriteStatesTraps(t *testing.T) {
+    require := require.New(t)
+
+    require.Error(requireWriteStates(nil))
+
+    readOnly := &CallInfo{CallFlags: ReadOnly}
+    err := requireWriteStates(readOnly)
+    require.Error(err)
+    require.Contains(err.Error(), "WriteStates")
+
+    writable := &CallInfo{CallFlags: ReadStates | WriteStates}
+    require.NoError(requireWriteStates(writable))
+}
+
+func TestRequireStateModificationsTraps(t *testing.T) {
+    require := require.New(t)
+
+    require.Error(requireStateModifications(nil))
+
+    // WriteStates alone is not enough: delete additionally requires
+    // AllowStateModifications.
+    writeOnly := &CallInfo{CallFlags: ReadStates | WriteStates}
+    err := requireStateModifications(writeOnly)
+    require.Error(err)
+    require.Contains(err.Error(), "AllowStateModifications")
+
+    privileged := &CallInfo{CallFlags: ReadStates | WriteStates | AllowStateModifications}
+    require.NoError(requireStateModifications(privileged))
+}